@@ -0,0 +1,173 @@
+//go:build !headless
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"foxes-rabbits-simulation/internal/chart"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+	"foxes-rabbits-simulation/internal/ui"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// runGUI opens the simulation and chart windows and runs the interactive loop
+func runGUI(ctx context.Context, world *simulation.World, cfg *config.Config, onTick func(*simulation.World)) error {
+	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
+		return fmt.Errorf("Failed to initialize SDL: %s", err)
+	}
+	defer sdl.Quit()
+
+	renderer, err := ui.NewRenderer("Foxes and Rabbits Simulation",
+		cfg.WorldWidth*cfg.AnimalSize, cfg.WorldHeight*cfg.AnimalSize, cfg)
+	if err != nil {
+		return fmt.Errorf("Failed to initialize renderer: %s", err)
+	}
+	defer renderer.Destroy()
+
+	var colors []config.Color
+	for _, population := range world.Populations {
+		colors = append(colors, population.Species.Color)
+	}
+	chartWindow, err := chart.NewChartWindow("Population Chart", cfg.WorldWidth*cfg.AnimalSize, cfg.WorldHeight*cfg.AnimalSize, colors)
+	if err != nil {
+		return fmt.Errorf("Failed to initialize chart window: %s", err)
+	}
+	defer chartWindow.Destroy()
+
+	chartWindow.AddDataPoint(populationCounts(world))
+
+	baseDelay := cfg.FrameTime * time.Millisecond
+	paused := false
+	speed := 0 // Speed is 2^speed times the configured frame rate
+
+	// Run until a window is closed or the process is interrupted
+	for ctx.Err() == nil {
+		// Handle events in both windows
+		input := renderer.HandleEvents()
+		if input.Quit {
+			break
+		}
+		mouseAction := input.Mouse
+
+		// Process keyboard actions
+		steps := 0
+		for _, keyAction := range input.KeyActions {
+			switch keyAction {
+			case "TogglePause":
+				paused = !paused
+			case "Step":
+				// Stepping pauses the simulation so the step can be looked at
+				paused = true
+				steps++
+			case "SpeedUp":
+				speed = min(speed+1, maxSpeed)
+			case "SlowDown":
+				speed = max(speed-1, -maxSpeed)
+			}
+		}
+		if !paused {
+			steps = 1
+		}
+
+		// Process mouse actions
+		if mouseAction.Action == "Inspect" {
+			renderer.Select(world, mouseAction.X, mouseAction.Y)
+		} else if mouseAction.Action != "" {
+			applyTool(world, cfg, mouseAction)
+		}
+
+		// Update simulation and chart
+		for i := 0; i < steps; i++ {
+			world.Update()
+			if onTick != nil {
+				onTick(world)
+			}
+			chartWindow.AddDataPoint(populationCounts(world))
+		}
+
+		// Update titles
+		populations := describePopulations(world)
+		renderer.SetTitle(fmt.Sprintf("Foxes and Rabbits Simulation - %s | %s",
+			populations, describeState(world.Tick, paused, speed)))
+		chartWindow.SetTitle(fmt.Sprintf("Population Chart - %s", populations))
+
+		// Render windows
+		renderer.Render(world)
+		chartWindow.Render()
+
+		time.Sleep(frameDelay(baseDelay, speed))
+	}
+
+	return nil
+}
+
+// applyTool applies a brush tool to every cell within its radius
+func applyTool(world *simulation.World, cfg *config.Config, action ui.MouseAction) {
+	for dx := -action.Radius; dx <= action.Radius; dx++ {
+		for dy := -action.Radius; dy <= action.Radius; dy++ {
+			// Brushes wrap around the edges of a torus
+			x, y, ok := world.Wrap(action.X+dx, action.Y+dy)
+			if dx*dx+dy*dy > action.Radius*action.Radius || !ok {
+				continue
+			}
+
+			switch action.Action {
+			case "Add":
+				if population := world.Population(action.Species); population != nil && !world.IsPositionBlocked(x, y) {
+					world.AddAnimal(simulation.NewAnimal(population.Species, x, y, cfg))
+				}
+			case "RemoveAnimal":
+				world.RemoveAnimalAt(x, y)
+			case "PaintGrass":
+				world.GrassGrid[x][y].SetAmount(cfg.GrassMaxAmount)
+			case "EraseGrass":
+				world.GrassGrid[x][y].SetAmount(0)
+			}
+		}
+	}
+}
+
+// populationCounts returns the size of every population, in the world's species order
+func populationCounts(world *simulation.World) []int {
+	counts := make([]int, len(world.Populations))
+	for i, population := range world.Populations {
+		counts[i] = len(population.Animals)
+	}
+	return counts
+}
+
+// describePopulations lists the size of every population for the window titles
+func describePopulations(world *simulation.World) string {
+	parts := make([]string, len(world.Populations))
+	for i, population := range world.Populations {
+		plural := population.Species.PluralName()
+		parts[i] = fmt.Sprintf("%s: %d", strings.ToUpper(plural[:1])+plural[1:], len(population.Animals))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// maxSpeed limits the speed controls to between 1/8 and 8 times the configured frame rate
+const maxSpeed = 3
+
+// frameDelay scales the configured delay between frames by the speed setting
+func frameDelay(base time.Duration, speed int) time.Duration {
+	if speed >= 0 {
+		return base >> speed
+	}
+	return base << -speed
+}
+
+// describeState summarizes the playback state for the window title
+func describeState(tick int, paused bool, speed int) string {
+	state := fmt.Sprintf("Tick %d | Speed x%g", tick, math.Pow(2, float64(speed)))
+	if paused {
+		state += " | Paused (space: resume, N: step)"
+	}
+	return state
+}
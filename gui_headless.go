@@ -0,0 +1,15 @@
+//go:build headless
+
+package main
+
+import (
+	"context"
+	"errors"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+)
+
+// runGUI is unavailable in binaries built with the headless tag, which don't link SDL
+func runGUI(ctx context.Context, world *simulation.World, cfg *config.Config, onTick func(*simulation.World)) error {
+	return errors.New("this binary was built without SDL support, run it with -headless")
+}
@@ -1,20 +1,24 @@
 package chart
 
 import (
+	"foxes-rabbits-simulation/internal/config"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type ChartWindow struct {
 	window    *sdl.Window
 	renderer  *sdl.Renderer
-	data      []struct{ Foxes, Rabbits int }
+	data      [][]int        // The size of every population at each point
+	colors    []config.Color // The line color of each population
 	maxPoints int
 	width     int32
 	height    int32
 	padding   int32
 }
 
-func NewChartWindow(title string, width, height int) (*ChartWindow, error) {
+// NewChartWindow opens a chart of the populations drawn in the given colors
+func NewChartWindow(title string, width, height int, colors []config.Color) (*ChartWindow, error) {
 	window, err := sdl.CreateWindow(title, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
 		int32(width), int32(height), sdl.WINDOW_SHOWN)
 	if err != nil {
@@ -23,13 +27,15 @@ func NewChartWindow(title string, width, height int) (*ChartWindow, error) {
 
 	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
 	if err != nil {
+		window.Destroy()
 		return nil, err
 	}
 
 	return &ChartWindow{
 		window:    window,
 		renderer:  renderer,
-		data:      make([]struct{ Foxes, Rabbits int }, 0),
+		data:      make([][]int, 0),
+		colors:    colors,
 		maxPoints: 300,
 		width:     int32(width),
 		height:    int32(height),
@@ -37,8 +43,9 @@ func NewChartWindow(title string, width, height int) (*ChartWindow, error) {
 	}, nil
 }
 
-func (c *ChartWindow) AddDataPoint(foxes, rabbits int) {
-	c.data = append(c.data, struct{ Foxes, Rabbits int }{foxes, rabbits})
+// AddDataPoint appends the size of every population, in the order of the chart's colors
+func (c *ChartWindow) AddDataPoint(counts []int) {
+	c.data = append(c.data, counts)
 	if len(c.data) > c.maxPoints {
 		c.data = c.data[1:]
 	}
@@ -56,11 +63,8 @@ func (c *ChartWindow) Render() {
 	// Find the maximum population for scaling
 	maxValue := 10 // Minimum scale
 	for _, point := range c.data {
-		if point.Foxes > maxValue {
-			maxValue = point.Foxes
-		}
-		if point.Rabbits > maxValue {
-			maxValue = point.Rabbits
+		for _, count := range point {
+			maxValue = max(maxValue, count)
 		}
 	}
 
@@ -102,24 +106,16 @@ func (c *ChartWindow) Render() {
 
 	xStep := float64(chartWidth) / float64(c.maxPoints-1)
 
-	// Draw fox population (red)
-	c.renderer.SetDrawColor(255, 0, 0, 255)
-	for i := 0; i < len(c.data)-1; i++ {
-		x1 := c.padding + int32(float64(i)*xStep)
-		y1 := c.height - c.padding - int32(float64(c.data[i].Foxes)/float64(maxValue)*float64(chartHeight))
-		x2 := c.padding + int32(float64(i+1)*xStep)
-		y2 := c.height - c.padding - int32(float64(c.data[i+1].Foxes)/float64(maxValue)*float64(chartHeight))
-		c.renderer.DrawLine(x1, y1, x2, y2)
-	}
-
-	// Draw rabbit population (blue)
-	c.renderer.SetDrawColor(0, 0, 255, 255)
-	for i := 0; i < len(c.data)-1; i++ {
-		x1 := c.padding + int32(float64(i)*xStep)
-		y1 := c.height - c.padding - int32(float64(c.data[i].Rabbits)/float64(maxValue)*float64(chartHeight))
-		x2 := c.padding + int32(float64(i+1)*xStep)
-		y2 := c.height - c.padding - int32(float64(c.data[i+1].Rabbits)/float64(maxValue)*float64(chartHeight))
-		c.renderer.DrawLine(x1, y1, x2, y2)
+	// Draw every population in its color
+	for species, color := range c.colors {
+		c.renderer.SetDrawColor(color.R, color.G, color.B, 255)
+		for i := 0; i < len(c.data)-1; i++ {
+			x1 := c.padding + int32(float64(i)*xStep)
+			y1 := c.height - c.padding - int32(float64(c.data[i][species])/float64(maxValue)*float64(chartHeight))
+			x2 := c.padding + int32(float64(i+1)*xStep)
+			y2 := c.height - c.padding - int32(float64(c.data[i+1][species])/float64(maxValue)*float64(chartHeight))
+			c.renderer.DrawLine(x1, y1, x2, y2)
+		}
 	}
 
 	c.renderer.Present()
@@ -128,3 +124,9 @@ func (c *ChartWindow) Render() {
 func (c *ChartWindow) SetTitle(title string) {
 	c.window.SetTitle(title)
 }
+
+// Destroy releases the SDL renderer and window
+func (c *ChartWindow) Destroy() {
+	c.renderer.Destroy()
+	c.window.Destroy()
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"time"
-
-	"github.com/veandco/go-sdl2/sdl"
 )
 
+// Color is an RGBA color, kept independent of any rendering backend
+type Color struct {
+	R, G, B, A uint8
+}
+
 type Config struct {
 	// World configuration
 	WorldWidth                   int
@@ -17,34 +20,118 @@ type Config struct {
 	AnimalSize                   int
 	ChanceToStayStillWhenFleeing float64
 
-	// Fox parameters
+	// Torus makes the world wrap around its edges, so animals leaving one side
+	// come back on the opposite one, instead of being stopped by walls
+	Torus bool
+
+	// PathfindingBudget enables searching for paths around obstacles when foxes chase
+	// and rabbits flee, expanding at most this many cells per search. 0 keeps the
+	// simple step toward or away from the nearest animal
+	PathfindingBudget int
+
+	// MutationRate is how much newborns' traits differ from their parent's, as the
+	// standard deviation relative to each trait. 0 makes every animal identical
+	MutationRate float64
+
+	// SexualReproduction makes animals female or male. Females give birth when a ready
+	// male is within reproduction range, and both parents pay half the reproduction cost.
+	// Otherwise any animal reproduces when another one of its species is nearby
+	SexualReproduction bool
+
+	// LitterSizeDistribution is how litter sizes are drawn around their mean: "fixed"
+	// rounds the mean up or down, "poisson" adds a Poisson distributed number of extra young
+	LitterSizeDistribution string
+
+	// ScentDeposit is the scent every animal leaves on its cell each tick, 0 disables scent.
+	// Each tick a ScentDiffusion fraction of the scent on a cell spreads to its passable
+	// neighbours, and a ScentDecay fraction of all scent fades
+	ScentDeposit   float64
+	ScentDiffusion float64
+	ScentDecay     float64
+
+	// Senses is how animals find prey and predators: "sight" sees them within vision,
+	// "scent" follows prey scent and avoids predator scent of at least ScentThreshold,
+	// and "both" smells whatever isn't in sight
+	Senses         string
+	ScentThreshold float64
+
+	// ParallelWorkers selects the parallel update when positive. Its results depend
+	// on the seed but not on the number of workers, and differ from the sequential update
+	ParallelWorkers int
+
+	// Fox parameters. FoxPrey lists the species foxes hunt, each worth
+	// FoxEnergyGainFromRabbit energy, and may include Grass, worth FoxEnergyGainFromGrass
+	FoxPrey                 []string
 	FoxInitialEnergy        int
 	FoxEnergyLossPerMove    int
 	FoxEnergyGainFromRabbit int
+	FoxEnergyGainFromGrass  int
 	FoxReproductionCost     int
-	FoxColor                sdl.Color
+	FoxColor                Color
 	FoxReproductionRange    int
 	FoxEatingRange          int
 	FoxFollowRabbitRange    int
 	FoxEatingCooldown       int
 	FoxReproductionCooldown int
+	FoxLitterSize           float64 // Mean number of young per birth, at least 1
+	FoxGestation            int     // Ticks from conception to birth, the mother pays the cost over them
 
-	// Rabbit parameters
+	// Foxes die of old age around FoxLifespan ticks, varied by a standard deviation of
+	// FoxLifespanSpread ticks, 0 lets them live as long as they find food.
+	// They can't reproduce before FoxMaturityAge, and less often in the second half of their life
+	FoxLifespan       int
+	FoxLifespanSpread int
+	FoxMaturityAge    int
+
+	// FoxUsesBurrows makes foxes hide in burrows from their own predators, like rabbits do
+	FoxUsesBurrows bool
+
+	// Rabbit parameters. RabbitPrey lists what rabbits eat like FoxPrey,
+	// grass is worth RabbitEnergyGainFromGrass and any animal nothing
+	RabbitPrey                 []string
 	RabbitInitialEnergy        int
 	RabbitEnergyLossPerMove    int
 	RabbitEnergyGainFromGrass  int
 	RabbitReproductionCost     int
-	RabbitColor                sdl.Color
+	RabbitColor                Color
 	RabbitReproductionRange    int
 	RabbitEscapeRange          int
 	RabbitEatingCooldown       int
 	RabbitReproductionCooldown int
+	RabbitLitterSize           float64
+	RabbitGestation            int
+
+	// Rabbit aging works like the fox parameters above
+	RabbitLifespan       int
+	RabbitLifespanSpread int
+	RabbitMaturityAge    int
+
+	// RabbitUsesBurrows makes rabbits run for burrows and hide in them from foxes
+	RabbitUsesBurrows bool
 
 	// Grass parameters
 	GrassGrowthRate    int
 	GrassMaxAmount     int
 	GrassRegrowthTimer int
-	GrassBaseColor     sdl.Color
+	GrassBaseColor     Color
+
+	// Terrain parameters. Meadow grows grass with the grass parameters above,
+	// water and rock block movement and grow nothing
+	ForestGrassGrowthRate int
+	ForestGrassMaxAmount  int
+	ForestColor           Color
+	WaterColor            Color
+	RockColor             Color
+
+	// Burrow parameters. BurrowCount burrows are dug on random cells, each hiding up to
+	// BurrowCapacity animals of species using burrows, like rabbits, from predators
+	BurrowCount    int
+	BurrowCapacity int
+	BurrowColor    Color
+
+	// ExtraSpecies adds species besides foxes and rabbits, e.g. wolves hunting foxes.
+	// They can only be set in config files
+	ExtraSpecies []Species
 }
 
 func NewConfig() *Config {
@@ -58,34 +145,74 @@ func NewConfig() *Config {
 		InitialGrass:                 3,
 		AnimalSize:                   8,
 		ChanceToStayStillWhenFleeing: 0.2,
+		Torus:                        false,
+		PathfindingBudget:            0,
+		MutationRate:                 0,
+		SexualReproduction:           false,
+		LitterSizeDistribution:       "fixed",
+		ScentDeposit:                 0,
+		ScentDiffusion:               0.4,
+		ScentDecay:                   0.05,
+		Senses:                       "sight",
+		ScentThreshold:               0.05,
+		ParallelWorkers:              0,
 
 		// Fox parameters
+		FoxPrey:                 []string{"rabbit"},
 		FoxInitialEnergy:        100,
 		FoxEnergyLossPerMove:    3,
 		FoxEnergyGainFromRabbit: 90,
+		FoxEnergyGainFromGrass:  0,
 		FoxReproductionCost:     200,
-		FoxColor:                sdl.Color{R: 255, G: 0, B: 0, A: 255},
+		FoxColor:                Color{R: 255, G: 0, B: 0, A: 255},
 		FoxReproductionRange:    2,
 		FoxEatingRange:          2,
 		FoxFollowRabbitRange:    30,
 		FoxEatingCooldown:       5,
 		FoxReproductionCooldown: 15,
+		FoxLitterSize:           1,
+		FoxGestation:            0,
+		FoxLifespan:             0,
+		FoxLifespanSpread:       0,
+		FoxMaturityAge:          0,
+		FoxUsesBurrows:          false,
 
 		// Rabbit parameters
+		RabbitPrey:                 []string{Grass},
 		RabbitInitialEnergy:        15,
 		RabbitEnergyLossPerMove:    1,
 		RabbitEnergyGainFromGrass:  3,
 		RabbitReproductionCost:     30,
-		RabbitColor:                sdl.Color{R: 0, G: 0, B: 255, A: 255},
+		RabbitColor:                Color{R: 0, G: 0, B: 255, A: 255},
 		RabbitReproductionRange:    3,
 		RabbitEscapeRange:          10,
 		RabbitEatingCooldown:       2,
 		RabbitReproductionCooldown: 5,
+		RabbitLitterSize:           1,
+		RabbitGestation:            0,
+		RabbitLifespan:             0,
+		RabbitLifespanSpread:       0,
+		RabbitMaturityAge:          0,
+		RabbitUsesBurrows:          true,
 
 		// Grass parameters
 		GrassGrowthRate:    1,
 		GrassMaxAmount:     3,
 		GrassRegrowthTimer: 50,
-		GrassBaseColor:     sdl.Color{R: 0, G: 100, B: 0, A: 255},
+		GrassBaseColor:     Color{R: 0, G: 100, B: 0, A: 255},
+
+		// Terrain parameters
+		ForestGrassGrowthRate: 1,
+		ForestGrassMaxAmount:  1,
+		ForestColor:           Color{R: 20, G: 60, B: 20, A: 255},
+		WaterColor:            Color{R: 40, G: 90, B: 200, A: 255},
+		RockColor:             Color{R: 120, G: 120, B: 120, A: 255},
+
+		// Burrow parameters
+		BurrowCount:    0,
+		BurrowCapacity: 4,
+		BurrowColor:    Color{R: 110, G: 70, B: 30, A: 255},
+
+		ExtraSpecies: []Species{},
 	}
 }
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Load reads a JSON config file on top of the defaults from NewConfig,
+// so any field omitted from the file keeps its default value
+func Load(path string) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".toml":
+		return nil, fmt.Errorf("%s: only JSON config files are supported", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg, err := Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Decode parses JSON config from r on top of the defaults and validates the result
+func Decode(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := NewConfig()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, describeDecodeError(data, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Write encodes cfg as indented JSON, suitable as a starting point for a config file
+func (c *Config) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// describeDecodeError turns JSON decoding errors into messages that point at the offending field or line
+func describeDecodeError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := lineAndColumn(data, syntaxErr.Offset)
+		return fmt.Errorf("line %d, column %d: %s", line, col, syntaxErr)
+	case errors.As(err, &typeErr):
+		line, _ := lineAndColumn(data, typeErr.Offset)
+		return fmt.Errorf("field %s (line %d): expected %s, got JSON %s", typeErr.Field, line, typeErr.Type, typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field"):
+		return fmt.Errorf("unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+// lineAndColumn converts a byte offset into 1-based line and column numbers
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	line, col := 1, 1
+	for i := 0; i < int(offset) && i < len(data); i++ {
+		if data[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Overrides collects "Field=Value" assignments from repeated command-line flags
+type Overrides []string
+
+func (o *Overrides) String() string {
+	return strings.Join(*o, ", ")
+}
+
+func (o *Overrides) Set(assignment string) error {
+	if !strings.Contains(assignment, "=") {
+		return fmt.Errorf("expected Field=Value, got %q", assignment)
+	}
+	*o = append(*o, assignment)
+	return nil
+}
+
+// Apply sets every overridden field on cfg in order and validates the result
+func (o Overrides) Apply(cfg *Config) error {
+	for _, assignment := range o {
+		name, value, _ := strings.Cut(assignment, "=")
+		if err := cfg.Set(strings.TrimSpace(name), strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return cfg.Validate()
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	colorType    = reflect.TypeOf(Color{})
+)
+
+// Set parses value and assigns it to the Config field with the given name.
+// Field names are matched case-insensitively, colors are written as R,G,B or R,G,B,A
+// and lists as comma-separated values
+func (c *Config) Set(name, value string) error {
+	field := reflect.ValueOf(c).Elem().FieldByNameFunc(func(fieldName string) bool {
+		return strings.EqualFold(fieldName, name)
+	})
+	if !field.IsValid() {
+		return fmt.Errorf("unknown config field %q", name)
+	}
+
+	if err := setField(field, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == durationType:
+		// Durations are stored as plain numbers, the same way they appear in config files
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		field.SetInt(n)
+	case field.Type() == colorType:
+		color, err := parseColor(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(color))
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		field.SetFloat(f)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		// Lists are written comma-separated, an empty value clears them
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("fields of type %s can't be set from the command line", field.Type())
+	}
+	return nil
+}
+
+// parseColor reads a color written as R,G,B or R,G,B,A with components from 0 to 255
+func parseColor(value string) (Color, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Color{}, fmt.Errorf("expected R,G,B or R,G,B,A, got %q", value)
+	}
+
+	components := []uint8{0, 0, 0, 255}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+		if err != nil {
+			return Color{}, fmt.Errorf("color components must be between 0 and 255, got %q", part)
+		}
+		components[i] = uint8(n)
+	}
+	return Color{R: components[0], G: components[1], B: components[2], A: components[3]}, nil
+}
@@ -0,0 +1,124 @@
+package config
+
+// Grass, when listed as prey, lets a species graze the grass of its own cell
+const Grass = "grass"
+
+// Species holds the parameters of one kind of animal. Foxes and rabbits are
+// configured by the Fox and Rabbit fields of Config, any other species by ExtraSpecies
+type Species struct {
+	Name   string // Lower case and singular, used in events and stats columns
+	Plural string // Used in stats columns and window titles, Name+"s" when empty
+	Symbol string // ASCII character placing the species in layout maps
+	Color  Color
+
+	// Prey lists the species this one hunts, Grass makes it graze.
+	// Its predators are the species that list it as prey
+	Prey []string
+
+	InitialCount         int
+	InitialEnergy        int
+	EnergyLossPerMove    int
+	EnergyGainFromPrey   int
+	EnergyGainFromGrass  int
+	ReproductionCost     int
+	ReproductionRange    int
+	EatingRange          int // Prey within this range can be caught, grass is only grazed on the animal's own cell
+	Vision               int // How far prey are chased and predators fled from
+	EatingCooldown       int
+	ReproductionCooldown int
+	LitterSize           float64
+	Gestation            int
+	Lifespan             int
+	LifespanSpread       int
+	MaturityAge          int
+	UsesBurrows          bool // Runs for the nearest burrow in sight when a predator is in sight, and hides in it
+}
+
+// PluralName returns Plural, or Name with an s appended if it's empty
+func (s *Species) PluralName() string {
+	if s.Plural != "" {
+		return s.Plural
+	}
+	return s.Name + "s"
+}
+
+// Eats reports whether food, a species name or Grass, is in the species' diet
+func (s *Species) Eats(food string) bool {
+	for _, prey := range s.Prey {
+		if prey == food {
+			return true
+		}
+	}
+	return false
+}
+
+// AllSpecies returns the parameters of every species in the world: foxes and
+// rabbits, built from their own fields, followed by ExtraSpecies.
+// Species are updated in this order each tick
+func (c *Config) AllSpecies() []Species {
+	fox := Species{
+		Name:                 "fox",
+		Plural:               "foxes",
+		Symbol:               "F",
+		Color:                c.FoxColor,
+		Prey:                 c.FoxPrey,
+		InitialCount:         c.InitialFoxes,
+		InitialEnergy:        c.FoxInitialEnergy,
+		EnergyLossPerMove:    c.FoxEnergyLossPerMove,
+		EnergyGainFromPrey:   c.FoxEnergyGainFromRabbit,
+		EnergyGainFromGrass:  c.FoxEnergyGainFromGrass,
+		ReproductionCost:     c.FoxReproductionCost,
+		ReproductionRange:    c.FoxReproductionRange,
+		EatingRange:          c.FoxEatingRange,
+		Vision:               c.FoxFollowRabbitRange,
+		EatingCooldown:       c.FoxEatingCooldown,
+		ReproductionCooldown: c.FoxReproductionCooldown,
+		LitterSize:           c.FoxLitterSize,
+		Gestation:            c.FoxGestation,
+		Lifespan:             c.FoxLifespan,
+		LifespanSpread:       c.FoxLifespanSpread,
+		MaturityAge:          c.FoxMaturityAge,
+		UsesBurrows:          c.FoxUsesBurrows,
+	}
+	rabbit := Species{
+		Name:                 "rabbit",
+		Plural:               "rabbits",
+		Symbol:               "R",
+		Color:                c.RabbitColor,
+		Prey:                 c.RabbitPrey,
+		InitialCount:         c.InitialRabbits,
+		InitialEnergy:        c.RabbitInitialEnergy,
+		EnergyLossPerMove:    c.RabbitEnergyLossPerMove,
+		EnergyGainFromGrass:  c.RabbitEnergyGainFromGrass,
+		ReproductionCost:     c.RabbitReproductionCost,
+		ReproductionRange:    c.RabbitReproductionRange,
+		Vision:               c.RabbitEscapeRange,
+		EatingCooldown:       c.RabbitEatingCooldown,
+		ReproductionCooldown: c.RabbitReproductionCooldown,
+		LitterSize:           c.RabbitLitterSize,
+		Gestation:            c.RabbitGestation,
+		Lifespan:             c.RabbitLifespan,
+		LifespanSpread:       c.RabbitLifespanSpread,
+		MaturityAge:          c.RabbitMaturityAge,
+		UsesBurrows:          c.RabbitUsesBurrows,
+	}
+	return append([]Species{fox, rabbit}, c.ExtraSpecies...)
+}
+
+// SetInitialCount sets how many animals of the named species the world starts with.
+// ExtraSpecies is copied before it's changed, so copies of c sharing it are unaffected
+func (c *Config) SetInitialCount(species string, count int) {
+	switch species {
+	case "fox":
+		c.InitialFoxes = count
+	case "rabbit":
+		c.InitialRabbits = count
+	default:
+		c.ExtraSpecies = append([]Species(nil), c.ExtraSpecies...)
+		for i := range c.ExtraSpecies {
+			if c.ExtraSpecies[i].Name == species {
+				c.ExtraSpecies[i].InitialCount = count
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks that every parameter is within a range the simulation can run with.
+// All problems are reported at once, one per line
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// World configuration
+	v.positive("WorldWidth", c.WorldWidth)
+	v.positive("WorldHeight", c.WorldHeight)
+	v.positive("AnimalSize", c.AnimalSize)
+	v.nonNegative("FrameTime", int(c.FrameTime))
+	v.nonNegative("InitialFoxes", c.InitialFoxes)
+	v.nonNegative("InitialRabbits", c.InitialRabbits)
+	v.between("InitialGrass", c.InitialGrass, 0, max(c.GrassMaxAmount, 0))
+	v.probability("ChanceToStayStillWhenFleeing", c.ChanceToStayStillWhenFleeing)
+	v.nonNegative("ParallelWorkers", c.ParallelWorkers)
+	v.nonNegative("PathfindingBudget", c.PathfindingBudget)
+	v.probability("MutationRate", c.MutationRate)
+	v.nonNegativeFloat("ScentDeposit", c.ScentDeposit)
+	v.probability("ScentDiffusion", c.ScentDiffusion)
+	v.probability("ScentDecay", c.ScentDecay)
+	v.nonNegativeFloat("ScentThreshold", c.ScentThreshold)
+	switch c.Senses {
+	case "sight":
+	case "scent", "both":
+		if c.ScentDeposit <= 0 {
+			v.fail("Senses %q needs a positive ScentDeposit", c.Senses)
+		}
+	default:
+		v.fail("Senses must be \"sight\", \"scent\" or \"both\", got %q", c.Senses)
+	}
+	if c.LitterSizeDistribution != "fixed" && c.LitterSizeDistribution != "poisson" {
+		v.fail("LitterSizeDistribution must be \"fixed\" or \"poisson\", got %q", c.LitterSizeDistribution)
+	}
+	initial := 0
+	for _, species := range c.AllSpecies() {
+		initial += species.InitialCount
+	}
+	if c.WorldWidth > 0 && c.WorldHeight > 0 && initial > c.WorldWidth*c.WorldHeight {
+		v.fail("The initial animals of every species must fit in the %dx%d world, got %d",
+			c.WorldWidth, c.WorldHeight, initial)
+	}
+
+	// Fox parameters
+	v.positive("FoxInitialEnergy", c.FoxInitialEnergy)
+	v.nonNegative("FoxEnergyLossPerMove", c.FoxEnergyLossPerMove)
+	v.nonNegative("FoxEnergyGainFromRabbit", c.FoxEnergyGainFromRabbit)
+	v.nonNegative("FoxEnergyGainFromGrass", c.FoxEnergyGainFromGrass)
+	v.nonNegative("FoxReproductionCost", c.FoxReproductionCost)
+	v.nonNegative("FoxReproductionRange", c.FoxReproductionRange)
+	v.nonNegative("FoxEatingRange", c.FoxEatingRange)
+	v.nonNegative("FoxFollowRabbitRange", c.FoxFollowRabbitRange)
+	v.nonNegative("FoxEatingCooldown", c.FoxEatingCooldown)
+	v.nonNegative("FoxReproductionCooldown", c.FoxReproductionCooldown)
+	v.atLeastOne("FoxLitterSize", c.FoxLitterSize)
+	v.nonNegative("FoxGestation", c.FoxGestation)
+	v.nonNegative("FoxLifespan", c.FoxLifespan)
+	v.nonNegative("FoxLifespanSpread", c.FoxLifespanSpread)
+	v.nonNegative("FoxMaturityAge", c.FoxMaturityAge)
+
+	// Rabbit parameters
+	v.positive("RabbitInitialEnergy", c.RabbitInitialEnergy)
+	v.nonNegative("RabbitEnergyLossPerMove", c.RabbitEnergyLossPerMove)
+	v.nonNegative("RabbitEnergyGainFromGrass", c.RabbitEnergyGainFromGrass)
+	v.nonNegative("RabbitReproductionCost", c.RabbitReproductionCost)
+	v.nonNegative("RabbitReproductionRange", c.RabbitReproductionRange)
+	v.nonNegative("RabbitEscapeRange", c.RabbitEscapeRange)
+	v.nonNegative("RabbitEatingCooldown", c.RabbitEatingCooldown)
+	v.nonNegative("RabbitReproductionCooldown", c.RabbitReproductionCooldown)
+	v.atLeastOne("RabbitLitterSize", c.RabbitLitterSize)
+	v.nonNegative("RabbitGestation", c.RabbitGestation)
+	v.nonNegative("RabbitLifespan", c.RabbitLifespan)
+	v.nonNegative("RabbitLifespanSpread", c.RabbitLifespanSpread)
+	v.nonNegative("RabbitMaturityAge", c.RabbitMaturityAge)
+
+	// Grass parameters
+	v.nonNegative("GrassGrowthRate", c.GrassGrowthRate)
+	v.positive("GrassMaxAmount", c.GrassMaxAmount)
+	v.nonNegative("GrassRegrowthTimer", c.GrassRegrowthTimer)
+
+	// Terrain parameters
+	v.nonNegative("ForestGrassGrowthRate", c.ForestGrassGrowthRate)
+	v.nonNegative("ForestGrassMaxAmount", c.ForestGrassMaxAmount)
+
+	// Burrow parameters
+	v.nonNegative("BurrowCount", c.BurrowCount)
+	v.positive("BurrowCapacity", c.BurrowCapacity)
+	if c.WorldWidth > 0 && c.WorldHeight > 0 && c.BurrowCount > c.WorldWidth*c.WorldHeight {
+		v.fail("BurrowCount must fit in the %dx%d world, got %d", c.WorldWidth, c.WorldHeight, c.BurrowCount)
+	}
+
+	// Extra species
+	for i := range c.ExtraSpecies {
+		v.species(fmt.Sprintf("ExtraSpecies[%d]", i), &c.ExtraSpecies[i])
+	}
+	v.speciesRelations(c.AllSpecies())
+
+	return errors.Join(v.errs...)
+}
+
+// species checks the parameters of an extra species, naming them after prefix
+func (v *validator) species(prefix string, s *Species) {
+	if s.Name == "" {
+		v.fail("%s.Name must not be empty", prefix)
+	} else {
+		prefix = fmt.Sprintf("%s (%s)", prefix, s.Name)
+	}
+	if len(s.Symbol) != 1 || s.Symbol[0] <= ' ' || s.Symbol[0] > '~' || strings.Contains(reservedSymbols, s.Symbol) {
+		v.fail("%s.Symbol must be a single ASCII character other than %q, got %q", prefix, reservedSymbols, s.Symbol)
+	}
+
+	v.nonNegative(prefix+".InitialCount", s.InitialCount)
+	v.positive(prefix+".InitialEnergy", s.InitialEnergy)
+	v.nonNegative(prefix+".EnergyLossPerMove", s.EnergyLossPerMove)
+	v.nonNegative(prefix+".EnergyGainFromPrey", s.EnergyGainFromPrey)
+	v.nonNegative(prefix+".EnergyGainFromGrass", s.EnergyGainFromGrass)
+	v.nonNegative(prefix+".ReproductionCost", s.ReproductionCost)
+	v.nonNegative(prefix+".ReproductionRange", s.ReproductionRange)
+	v.nonNegative(prefix+".EatingRange", s.EatingRange)
+	v.nonNegative(prefix+".Vision", s.Vision)
+	v.nonNegative(prefix+".EatingCooldown", s.EatingCooldown)
+	v.nonNegative(prefix+".ReproductionCooldown", s.ReproductionCooldown)
+	v.atLeastOne(prefix+".LitterSize", s.LitterSize)
+	v.nonNegative(prefix+".Gestation", s.Gestation)
+	v.nonNegative(prefix+".Lifespan", s.Lifespan)
+	v.nonNegative(prefix+".LifespanSpread", s.LifespanSpread)
+	v.nonNegative(prefix+".MaturityAge", s.MaturityAge)
+}
+
+// reservedSymbols are the characters of ASCII layout maps that stand for terrain, grass or burrows
+const reservedSymbols = ". 0123456789t~#o"
+
+// speciesRelations checks that species can be told apart and only hunt species that exist
+func (v *validator) speciesRelations(all []Species) {
+	names := map[string]bool{Grass: true}
+	symbols := map[string]bool{}
+	for _, s := range all {
+		if s.Symbol != "" && symbols[s.Symbol] {
+			v.fail("Species symbol %q is used more than once", s.Symbol)
+		}
+		symbols[s.Symbol] = true
+
+		switch {
+		case s.Name == "":
+		case s.Name == Grass:
+			v.fail("Species can't be named %q", Grass)
+		case names[s.Name]:
+			v.fail("Species name %q is used more than once", s.Name)
+		default:
+			names[s.Name] = true
+		}
+	}
+
+	for _, s := range all {
+		for _, prey := range s.Prey {
+			if prey == s.Name {
+				v.fail("Species %q can't hunt itself", s.Name)
+			} else if !names[prey] {
+				v.fail("Species %q hunts unknown species %q", s.Name, prey)
+			}
+		}
+	}
+}
+
+// validator collects every failed check instead of stopping at the first one
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) positive(name string, value int) {
+	if value <= 0 {
+		v.fail("%s must be positive, got %d", name, value)
+	}
+}
+
+func (v *validator) nonNegative(name string, value int) {
+	if value < 0 {
+		v.fail("%s must not be negative, got %d", name, value)
+	}
+}
+
+func (v *validator) between(name string, value, low, high int) {
+	if value < low || value > high {
+		v.fail("%s must be between %d and %d, got %d", name, low, high, value)
+	}
+}
+
+func (v *validator) probability(name string, value float64) {
+	if value < 0 || value > 1 {
+		v.fail("%s must be between 0 and 1, got %g", name, value)
+	}
+}
+
+func (v *validator) nonNegativeFloat(name string, value float64) {
+	if value < 0 {
+		v.fail("%s must not be negative, got %g", name, value)
+	}
+}
+
+func (v *validator) atLeastOne(name string, value float64) {
+	if value < 1 {
+		v.fail("%s must be at least 1, got %g", name, value)
+	}
+}
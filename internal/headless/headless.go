@@ -0,0 +1,55 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"foxes-rabbits-simulation/internal/simulation"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run advances the world for the given number of ticks without any rendering.
+// A population line is written every reportEvery ticks, followed by a summary.
+// onTick, if not nil, is called after every update. Cancelling ctx stops the run early
+func Run(ctx context.Context, world *simulation.World, ticks, reportEvery int, out io.Writer, onTick func(*simulation.World)) {
+	start := time.Now()
+	done := 0
+
+	header := "tick"
+	for _, population := range world.Populations {
+		header += "\t" + population.Species.PluralName()
+	}
+	fmt.Fprintln(out, header)
+	report(out, world)
+
+	for i := 1; i <= ticks && ctx.Err() == nil; i++ {
+		world.Update()
+		done++
+		if onTick != nil {
+			onTick(world)
+		}
+
+		if reportEvery > 0 && (world.Tick%reportEvery == 0 || i == ticks) {
+			report(out, world)
+		}
+	}
+
+	elapsed := time.Since(start)
+	final := make([]string, len(world.Populations))
+	for i, population := range world.Populations {
+		final[i] = fmt.Sprintf("%s: %d", population.Species.PluralName(), len(population.Animals))
+	}
+	fmt.Fprintf(out, "# %d ticks in %s (%.1f ticks/s), final %s\n",
+		done, elapsed.Round(time.Millisecond), float64(done)/elapsed.Seconds(), strings.Join(final, ", "))
+}
+
+// report writes the tick and the size of every population on one line
+func report(out io.Writer, world *simulation.World) {
+	line := strconv.Itoa(world.Tick)
+	for _, population := range world.Populations {
+		line += "\t" + strconv.Itoa(len(population.Animals))
+	}
+	fmt.Fprintln(out, line)
+}
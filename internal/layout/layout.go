@@ -0,0 +1,145 @@
+package layout
+
+import (
+	"bufio"
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cell is one parsed cell of a layout. Grass is -1 to use the configured InitialGrass,
+// and occupant names the species of the animal standing on it, if any
+type cell struct {
+	terrain  simulation.Terrain
+	grass    int
+	occupant string
+	burrow   bool
+}
+
+// Layout is a world's starting terrain, grass and animals, stored row by row
+type Layout struct {
+	Width  int
+	Height int
+	cells  []cell
+}
+
+// Load reads a layout from a PNG image or, for any other extension, an ASCII map
+func Load(path string, cfg *config.Config) (*Layout, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		return ReadPNG(file, cfg)
+	}
+	return ReadASCII(file, cfg)
+}
+
+// ReadASCII reads a map with one character per cell and one line per row:
+//
+//	.  meadow with the configured InitialGrass (a space works too)
+//	0-9  meadow with that much grass, capped at GrassMaxAmount
+//	F  fox on meadow
+//	R  rabbit on meadow
+//	t  forest
+//	~  water
+//	#  rock
+//	o  burrow on meadow
+//
+// Extra species are placed by their configured Symbol.
+// All rows must be the same length. Trailing empty lines are ignored
+func ReadASCII(in io.Reader, cfg *config.Config) (*Layout, error) {
+	symbols := make(map[byte]string)
+	for _, species := range cfg.AllSpecies() {
+		symbols[species.Symbol[0]] = species.Name
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("map is empty")
+	}
+
+	layout := &Layout{Width: len(lines[0]), Height: len(lines)}
+	for row, line := range lines {
+		if len(line) != layout.Width {
+			return nil, fmt.Errorf("line %d: expected %d characters like the first line, got %d", row+1, layout.Width, len(line))
+		}
+		for col, char := range []byte(line) {
+			c := cell{grass: -1}
+			switch {
+			case char == '.' || char == ' ':
+			case char >= '0' && char <= '9':
+				c.grass = int(char - '0')
+			case symbols[char] != "":
+				c.occupant = symbols[char]
+			case char == 't':
+				c.terrain = simulation.Forest
+			case char == '~':
+				c.terrain = simulation.Water
+			case char == '#':
+				c.terrain = simulation.Rock
+			case char == 'o':
+				c.burrow = true
+			default:
+				return nil, fmt.Errorf("line %d, column %d: unknown map character %q", row+1, col+1, char)
+			}
+			layout.cells = append(layout.cells, c)
+		}
+	}
+	return layout, nil
+}
+
+// NewWorld creates a world sized to the layout and populated from it instead of at random.
+// The world's config is a copy of cfg with the size, initial populations and burrows of the layout
+func (l *Layout) NewWorld(cfg *config.Config, seed uint64) (*simulation.World, error) {
+	worldConfig := *cfg
+	worldConfig.WorldWidth, worldConfig.WorldHeight = l.Width, l.Height
+	worldConfig.BurrowCount = 0
+	counts := make(map[string]int)
+	for _, c := range l.cells {
+		counts[c.occupant]++
+		if c.burrow {
+			worldConfig.BurrowCount++
+		}
+	}
+	for _, species := range cfg.AllSpecies() {
+		worldConfig.SetInitialCount(species.Name, counts[species.Name])
+	}
+	if err := worldConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	world := simulation.NewWorld(&worldConfig, seed)
+	for i, c := range l.cells {
+		x, y := i%l.Width, i/l.Width
+		world.SetTerrain(x, y, c.terrain)
+		if c.grass >= 0 {
+			world.GrassGrid[x][y].SetAmount(c.grass)
+		}
+		if c.burrow {
+			world.AddBurrow(x, y)
+		}
+
+		if c.occupant != "" {
+			population := world.Population(c.occupant)
+			world.AddAnimal(simulation.NewAnimal(population.Species, x, y, &worldConfig))
+		}
+	}
+	return world, nil
+}
@@ -0,0 +1,66 @@
+package layout
+
+import (
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// ReadPNG reads a layout with one pixel per cell. Pixels in the color of a species,
+// like FoxColor or RabbitColor, place an animal of it, pixels in ForestColor, WaterColor or RockColor set the terrain,
+// pixels in BurrowColor dig a burrow on meadow, and fully transparent pixels are meadow with the configured InitialGrass.
+// Any other pixel is meadow whose grass amount is read from its green channel,
+// on the same scale the renderer shades grass from GrassBaseColor
+func ReadPNG(in io.Reader, cfg *config.Config) (*Layout, error) {
+	img, err := png.Decode(in)
+	if err != nil {
+		return nil, fmt.Errorf("reading PNG: %w", err)
+	}
+
+	bounds := img.Bounds()
+	layout := &Layout{Width: bounds.Dx(), Height: bounds.Dy()}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			layout.cells = append(layout.cells, pixelCell(img.At(x, y), cfg))
+		}
+	}
+	return layout, nil
+}
+
+func pixelCell(pixel color.Color, cfg *config.Config) cell {
+	c := color.NRGBAModel.Convert(pixel).(color.NRGBA)
+	if c.A == 0 {
+		return cell{grass: -1}
+	}
+
+	matches := func(want config.Color) bool {
+		return c.R == want.R && c.G == want.G && c.B == want.B
+	}
+	for _, species := range cfg.AllSpecies() {
+		if matches(species.Color) {
+			return cell{grass: -1, occupant: species.Name}
+		}
+	}
+	switch {
+	case matches(cfg.ForestColor):
+		return cell{grass: -1, terrain: simulation.Forest}
+	case matches(cfg.WaterColor):
+		return cell{grass: -1, terrain: simulation.Water}
+	case matches(cfg.RockColor):
+		return cell{grass: -1, terrain: simulation.Rock}
+	case matches(cfg.BurrowColor):
+		return cell{grass: -1, burrow: true}
+	}
+
+	// Invert the renderer's shading, where green goes from the base color's to 255 at full grass
+	base := float64(cfg.GrassBaseColor.G)
+	ratio := 0.0
+	if base < 255 {
+		ratio = max(0, float64(c.G)-base) / (255 - base)
+	}
+	return cell{grass: int(math.Round(ratio * float64(cfg.GrassMaxAmount)))}
+}
@@ -0,0 +1,63 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// deathCause records why an animal died
+type deathCause int
+
+const (
+	starvation deathCause = iota // Ran out of energy, the cause unless another one was recorded
+	predation
+	oldAge
+)
+
+// assignLifespan draws the age an animal placed in the world will die at: mean ticks,
+// varied by a normal distribution with a standard deviation of spread. Animals that
+// already have a lifespan, like those restored from a snapshot, keep it.
+// A mean of 0 leaves the animal ageless
+func (w *World) assignLifespan(a *Animal, mean, spread int) {
+	if a.Lifespan != 0 || mean <= 0 {
+		return
+	}
+	a.Lifespan = mean
+	if spread > 0 {
+		a.Lifespan = max(mean+int(math.Round(w.rng.NormFloat64()*float64(spread))), 1)
+	}
+}
+
+// ageAnimals makes every living animal of a population one tick older. Those reaching
+// their lifespan die right away, freeing their cell, and are counted in the population's stats
+func (w *World) ageAnimals(population *Population) {
+	for _, a := range population.Animals {
+		if a.IsDead() {
+			continue
+		}
+
+		a.Age++
+		if a.Lifespan > 0 && a.Age >= a.Lifespan {
+			a.Energy = 0
+			a.cause = oldAge
+			population.LastTick.DiedOfAge++
+			w.emit(Event{Kind: DiedOfAge, Animal: a, Position: a.Position})
+			w.vacate(a)
+			population.index.Remove(a)
+		}
+	}
+}
+
+// fertile decides whether the animal's age lets it reproduce now. Animals can't
+// reproduce before maturityAge, and their fertility falls from full at half their
+// lifespan to none at its end
+func (a *Animal) fertile(maturityAge int, rng *rand.Rand) bool {
+	if a.Age < maturityAge {
+		return false
+	}
+	if a.Lifespan <= 0 || a.Age <= a.Lifespan/2 {
+		return true
+	}
+	remaining := float64(a.Lifespan-a.Age) / float64(a.Lifespan-a.Lifespan/2)
+	return rng.Float64() < remaining
+}
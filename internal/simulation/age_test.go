@@ -0,0 +1,32 @@
+package simulation
+
+import (
+	"foxes-rabbits-simulation/internal/config"
+	"math"
+	"testing"
+)
+
+func TestLifespansRoundOffsets(t *testing.T) {
+	world := NewWorld(config.NewConfig(), 1)
+	species := world.Population("rabbit").Species
+
+	// With a spread of 1, offsets rounded to the nearest tick are 0 for 38% of animals,
+	// while truncating them toward zero would give 68%
+	const draws, mean = 10000, 100
+	total, unchanged := 0, 0
+	for range draws {
+		animal := NewAnimal(species, 0, 0, world.Config)
+		world.assignLifespan(animal, mean, 1)
+		total += animal.Lifespan
+		if animal.Lifespan == mean {
+			unchanged++
+		}
+	}
+
+	if average := float64(total) / draws; math.Abs(average-mean) > 0.05 {
+		t.Fatalf("average lifespan %.3f, expected about %d", average, mean)
+	}
+	if share := float64(unchanged) / draws; share < 0.35 || share > 0.42 {
+		t.Fatalf("%.0f%% of lifespans equal the mean, expected about 38%%", share*100)
+	}
+}
@@ -2,77 +2,70 @@ package simulation
 
 import (
 	"foxes-rabbits-simulation/internal/config"
-	"math/rand"
+	"math/rand/v2"
 )
 
-type Animal interface {
-	IsDead() bool
-	GetPosition() Position
-	CanEat(cooldown int) bool
-}
-
-type AnimalBase struct {
+// Animal is an individual of any species
+type Animal struct {
+	ID                     int
+	Species                *Species
 	Position               Position
 	Energy                 int
 	Config                 *config.Config
 	TurnsSinceEaten        int
 	TurnsSinceReproduction int
+	Traits                 Traits
+	Sex                    Sex
+	Pregnancy              *Pregnancy // nil unless the animal carries young
+	Burrow                 *Burrow    // nil unless the animal hides in a burrow, off the map
+
+	// Age counts the ticks the animal has lived, it dies of old age on reaching
+	// its Lifespan. A Lifespan of 0 never runs out
+	Age      int
+	Lifespan int
+
+	// cause tells why a dead animal died
+	cause deathCause
 }
 
-func (a *AnimalBase) IsDead() bool {
-	return a.Energy <= 0
+// NewAnimal creates an animal of the given species with the species' initial energy and traits.
+// It's put into the world with World.AddAnimal
+func NewAnimal(species *Species, x, y int, cfg *config.Config) *Animal {
+	return &Animal{
+		Species:  species,
+		Position: Position{X: x, Y: y},
+		Energy:   species.InitialEnergy,
+		Config:   cfg,
+		Traits:   species.traits(),
+	}
 }
 
-func (a *AnimalBase) GetPosition() Position {
-	return a.Position
+func (a *Animal) IsDead() bool {
+	return a.Energy <= 0
 }
 
-// FindEmptyAdjacentPosition finds an empty position nearby animal
+// FindEmptyAdjacentPosition finds an empty, passable position nearby animal
 func FindEmptyAdjacentPosition(pos Position, world *World, maxAttempts int) (int, int, bool) {
 	for attempts := 0; attempts < maxAttempts; attempts++ {
-		dx := rand.Intn(3) - 1 // -1, 0, or 1
-		dy := rand.Intn(3) - 1 // -1, 0, or 1
-
-		newX := pos.X + dx
-		newY := pos.Y + dy
+		dx := world.rng.IntN(3) - 1 // -1, 0, or 1
+		dy := world.rng.IntN(3) - 1 // -1, 0, or 1
 
-		// Check if the position is valid and empty
-		if newX >= 0 && newX < world.Width &&
-			newY >= 0 && newY < world.Height &&
-			!world.IsPositionOccupied(newX, newY) {
+		// Check if the position is valid and empty, wrapping around the edges of a torus
+		newX, newY, ok := world.Wrap(pos.X+dx, pos.Y+dy)
+		if ok && !world.IsPositionBlocked(newX, newY) {
 			return newX, newY, true
 		}
 	}
 	return 0, 0, false
 }
 
-// IsNearbyAnimal checks if there's a nearby animal of the same type
-func IsNearbyAnimal[T Animal](animal T, others []T, range_ int) bool {
-	for _, other := range others {
-		// Skip checking against itself
-		if animal.GetPosition() == other.GetPosition() {
-			continue
-		}
-
-		// Calculate distance between animals
-		dx := abs(animal.GetPosition().X - other.GetPosition().X)
-		dy := abs(animal.GetPosition().Y - other.GetPosition().Y)
-
-		// Check if animal is within the configured reproduction range
-		if dx <= range_ && dy <= range_ {
-			return true
-		}
-	}
-	return false
-}
-
 // CanEat returns true if enough turns have passed since last eating
-func (a *AnimalBase) CanEat(cooldown int) bool {
+func (a *Animal) CanEat(cooldown int) bool {
 	return a.TurnsSinceEaten >= cooldown
 }
 
 // CanReproduce returns true if enough turns have passed since last reproduction
-func (a *AnimalBase) CanReproduce(cooldown int) bool {
+func (a *Animal) CanReproduce(cooldown int) bool {
 	return a.TurnsSinceReproduction >= cooldown
 }
 
@@ -84,61 +77,55 @@ func abs(x int) int {
 	return x
 }
 
-func FindNearestAnimal[T Animal](from Animal, animals []T, maxRange int) (T, bool) {
-	var nearest T
-	foundAnimal := false
-	minDistance := maxRange + 1
-
-	for _, animal := range animals {
-		dx := abs(from.GetPosition().X - animal.GetPosition().X)
-		dy := abs(from.GetPosition().Y - animal.GetPosition().Y)
-
-		distance := dx + dy
-
-		if distance <= maxRange && (distance < minDistance || !foundAnimal) {
-			nearest = animal
-			minDistance = distance
-			foundAnimal = true
-		}
+// sign returns -1, 0 or 1 for negative, zero or positive x
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
 	}
+	return 0
+}
 
-	return nearest, foundAnimal
+func (a *Animal) MoveRandomly(world *World) bool {
+	return a.applyMove(a.planRandomMove(world, world.rng), world)
 }
 
-func (a *AnimalBase) MoveRandomly(world *World) bool {
+// planRandomMove picks a random free neighbouring cell without moving, skipping water and rock
+func (a *Animal) planRandomMove(world *World, rng *rand.Rand) movePlan {
 	directions := []struct{ dx, dy int }{
 		{1, 0}, {-1, 0}, {0, 1}, {0, -1}, // Right, Left, Down, Up
 	}
 
-	rand.Shuffle(len(directions), func(i, j int) {
+	rng.Shuffle(len(directions), func(i, j int) {
 		directions[i], directions[j] = directions[j], directions[i]
 	})
 
 	// Try each direction
 	for _, dir := range directions {
-		newX := a.Position.X + dir.dx
-		newY := a.Position.Y + dir.dy
-
-		if newX >= 0 && newX < world.Width &&
-			newY >= 0 && newY < world.Height &&
-			!world.IsPositionOccupied(newX, newY) {
-			a.Position.X, a.Position.Y = newX, newY
-			return true
+		if plan := planStep(a.Position.X+dir.dx, a.Position.Y+dir.dy, world); plan.OK {
+			return plan
 		}
 	}
 
-	return false // Couldn't move
+	return movePlan{} // Couldn't move
 }
 
 // MoveDirectionally moves the animal toward or away from a target position
 // If moveToward is true, animal moves toward the target, otherwise it moves away
-func (a *AnimalBase) MoveDirectionally(targetPos Position, world *World, moveToward bool) bool {
-	if !moveToward && rand.Float64() < a.Config.ChanceToStayStillWhenFleeing {
-		return false
+func (a *Animal) MoveDirectionally(targetPos Position, world *World, moveToward bool) bool {
+	return a.applyMove(a.planDirectionalMove(targetPos, world, moveToward, world.rng), world)
+}
+
+// planDirectionalMove picks the cell MoveDirectionally would step onto without moving
+func (a *Animal) planDirectionalMove(targetPos Position, world *World, moveToward bool, rng *rand.Rand) movePlan {
+	if !moveToward && rng.Float64() < a.Config.ChanceToStayStillWhenFleeing {
+		return movePlan{}
 	}
 
-	dx := targetPos.X - a.Position.X
-	dy := targetPos.Y - a.Position.Y
+	// On a torus the shortest way may cross an edge
+	dx, dy := world.offset(a.Position, targetPos)
 
 	// Reverse direction if moving away
 	if !moveToward {
@@ -146,33 +133,225 @@ func (a *AnimalBase) MoveDirectionally(targetPos Position, world *World, moveTow
 		dy = -dy
 	}
 
+	x, y := a.Position.X, a.Position.Y
+
 	// Try to move horizontally first if dx is larger
-	if abs(dx) >= abs(dy) {
-		if dx > 0 && a.Position.X < world.Width-1 && !world.IsPositionOccupied(a.Position.X+1, a.Position.Y) {
-			a.Position.X++
-			return true
-		} else if dx < 0 && a.Position.X > 0 && !world.IsPositionOccupied(a.Position.X-1, a.Position.Y) {
-			a.Position.X--
-			return true
+	if abs(dx) >= abs(dy) && dx != 0 {
+		if plan := planStep(x+sign(dx), y, world); plan.OK {
+			return plan
 		}
 	}
 
 	// Try to move vertically if horizontal movement not possible
-	if dy > 0 && a.Position.Y < world.Height-1 && !world.IsPositionOccupied(a.Position.X, a.Position.Y+1) {
-		a.Position.Y++
-		return true
-	} else if dy < 0 && a.Position.Y > 0 && !world.IsPositionOccupied(a.Position.X, a.Position.Y-1) {
-		a.Position.Y--
-		return true
+	if dy != 0 {
+		return planStep(x, y+sign(dy), world)
+	}
+
+	return movePlan{}
+}
+
+// planStep plans a step onto a neighbouring cell, wrapped onto a torus, if it's free
+func planStep(x, y int, world *World) movePlan {
+	x, y, ok := world.Wrap(x, y)
+	if !ok || world.IsPositionBlocked(x, y) {
+		return movePlan{}
+	}
+	return movePlan{To: Position{X: x, Y: y}, OK: true}
+}
+
+// movePlan is the cell an animal decided to step onto, if any
+type movePlan struct {
+	To Position
+	OK bool
+}
+
+// applyMove carries out a planned step, unless another animal took the cell since it was planned
+func (a *Animal) applyMove(plan movePlan, world *World) bool {
+	if !plan.OK || world.IsPositionBlocked(plan.To.X, plan.To.Y) {
+		return false
 	}
+	a.moveTo(plan.To, world)
+	return true
+}
 
-	return false
+// moveTo steps the animal onto pos, keeping the world's occupancy grid and spatial index in sync
+func (a *Animal) moveTo(pos Position, world *World) {
+	world.relocate(a.Position, pos)
+	a.Position = pos
 }
 
-func (a *AnimalBase) MoveToward(targetPos Position, world *World) bool {
+func (a *Animal) MoveToward(targetPos Position, world *World) bool {
 	return a.MoveDirectionally(targetPos, world, true)
 }
 
-func (a *AnimalBase) MoveAwayFrom(targetPos Position, world *World) bool {
+func (a *Animal) MoveAwayFrom(targetPos Position, world *World) bool {
 	return a.MoveDirectionally(targetPos, world, false)
 }
+
+func (a *Animal) Move(world *World) {
+	a.finishMove(a.planMove(world, world.rng), world)
+}
+
+// planMove decides where the animal steps this tick without changing the world.
+// It runs for a burrow or flees from the nearest predator it sees, or flees from the
+// scent of predators, otherwise chases the nearest prey or follows its scent, otherwise wanders
+func (a *Animal) planMove(world *World, rng *rand.Rand) movePlan {
+	budget := a.Config.PathfindingBudget
+	sight, smell := a.Config.Senses != "scent", a.Config.Senses != "sight"
+
+	// If found a predator within range, try to reach a burrow to hide in, otherwise move away
+	// from it, or from every predator in range toward the safest reachable cell if pathfinding is enabled
+	if predator, found := world.nearest(a.Species.predators, a.Position, a.Traits.Vision); sight && found {
+		if plan, ok := a.planHide(world, budget, rng); ok {
+			return plan
+		}
+
+		var plan movePlan
+		if budget > 0 {
+			plan = a.planFlight(a.nearbyPredators(world), world, budget, rng)
+		} else {
+			plan = a.planDirectionalMove(predator.Position, world, false, rng)
+		}
+		if plan.OK {
+			return plan
+		}
+	}
+
+	// Otherwise move away from predator scent
+	if smell {
+		if plan := a.planScentFlight(world, rng); plan.OK {
+			return plan
+		}
+	}
+
+	// If found prey within range, try to move toward it, around obstacles if pathfinding is enabled
+	if prey, found := world.nearest(a.Species.prey, a.Position, a.Traits.Vision); sight && found {
+		var plan movePlan
+		if budget > 0 {
+			plan = a.planChase(prey.Position, world, budget)
+		} else {
+			plan = a.planDirectionalMove(prey.Position, world, true, rng)
+		}
+		if plan.OK {
+			return plan
+		}
+	}
+
+	// Otherwise follow the scent of prey
+	if smell {
+		if plan := a.planScentMove(a.Species.prey, world, false); plan.OK {
+			return plan
+		}
+	}
+
+	// Nothing to flee from or chase, or couldn't move, move randomly
+	return a.planRandomMove(world, rng)
+}
+
+// nearbyPredators returns the positions of the predators the animal can see
+func (a *Animal) nearbyPredators(world *World) []Position {
+	var predators []Position
+	for _, species := range a.Species.predators {
+		index := world.populationOf(species).index
+		index.forEachInRange(a.Position, a.Traits.Vision, func(predator *Animal) bool {
+			dx, dy := index.distance(a.Position, predator.Position)
+			if dx+dy <= a.Traits.Vision {
+				predators = append(predators, predator.Position)
+			}
+			return true
+		})
+	}
+	return predators
+}
+
+// finishMove steps onto the planned cell, and on as far as the animal's speed allows,
+// and pays the energy cost of moving
+func (a *Animal) finishMove(plan movePlan, world *World) {
+	a.takeSteps(plan, world, func() movePlan { return a.planMove(world, world.rng) })
+	a.Energy -= a.moveCost(a.Species.EnergyLossPerMove)
+}
+
+// Eat catches the nearest prey within eating range, or else grazes the animal's
+// own cell if its species eats grass, once its eating cooldown has passed
+func (a *Animal) Eat(world *World) {
+	a.TurnsSinceEaten++
+
+	if !a.CanEat(a.Species.EatingCooldown) {
+		return
+	}
+
+	if prey, found := world.nearest(a.Species.prey, a.Position, a.Species.EatingRange); found {
+		a.Energy += a.energyFrom(a.Species.EnergyGainFromPrey)
+		a.TurnsSinceEaten = 0
+		world.kill(prey, a)
+		return
+	}
+
+	if grass := world.GrassGrid[a.Position.X][a.Position.Y]; a.Species.grazes && grass.Amount > 0 {
+		grass.Eat(1)
+		a.Energy += a.energyFrom(a.Species.EnergyGainFromGrass)
+		a.TurnsSinceEaten = 0
+	}
+}
+
+// Reproduce conceives a litter when the animal is ready, and places the young around
+// it once born, after the species' gestation. With sexual reproduction only females
+// conceive, and a ready male within range must share the cost, otherwise another
+// animal of the species nearby is enough
+func (a *Animal) Reproduce(world *World) []*Animal {
+	a.TurnsSinceReproduction++
+	species := a.Species
+
+	// A pregnant animal carries its litter until it's due
+	if a.Pregnancy != nil {
+		if pregnancy, due := a.gestate(); due {
+			return a.giveBirth(pregnancy, world)
+		}
+		return nil
+	}
+
+	if !a.CanReproduce(species.ReproductionCooldown) || a.Energy < a.Traits.ReproductionThreshold {
+		return nil
+	}
+
+	index := world.populationOf(species).index
+	traits, cost := a.Traits, species.ReproductionCost
+	if a.Config.SexualReproduction {
+		if a.Sex != Female {
+			return nil
+		}
+		father, found := findMate(a, index)
+		if !found || !a.fertile(species.MaturityAge, world.rng) {
+			return nil
+		}
+		father.Energy -= cost / 2
+		father.TurnsSinceReproduction = 0
+		traits = traits.mix(father.Traits, world.rng)
+		cost -= cost / 2
+	} else if !index.AnyWithin(a, species.ReproductionRange) || !a.fertile(species.MaturityAge, world.rng) {
+		return nil
+	}
+	a.TurnsSinceReproduction = 0
+
+	size := litterSize(species.LitterSize, a.Config.LitterSizeDistribution, world.rng)
+	if pregnancy, due := a.conceive(species.Gestation, size, cost, traits); due {
+		return a.giveBirth(pregnancy, world)
+	}
+	return nil
+}
+
+// giveBirth places the young of a pregnancy around the mother, as many as fit
+func (a *Animal) giveBirth(pregnancy *Pregnancy, world *World) []*Animal {
+	var litter []*Animal
+	for range pregnancy.LitterSize {
+		newX, newY, found := FindEmptyAdjacentPosition(a.Position, world, 8)
+		if !found {
+			break
+		}
+		young := NewAnimal(a.Species, newX, newY, a.Config)
+		young.Traits = pregnancy.Traits.inherit(world.rng, a.Config.MutationRate, a.Species.ReproductionCost)
+		world.place(young)
+		litter = append(litter, young)
+	}
+	return litter
+}
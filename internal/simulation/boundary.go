@@ -0,0 +1,69 @@
+package simulation
+
+// Wrap maps a cell that may lie outside the world onto it. On a torus the
+// coordinates wrap around the edges, with walls it reports false outside the world
+func (w *World) Wrap(x, y int) (int, int, bool) {
+	if w.torus {
+		return wrapAxis(x, w.Width), wrapAxis(y, w.Height), true
+	}
+	return x, y, x >= 0 && x < w.Width && y >= 0 && y < w.Height
+}
+
+// Torus reports whether the world wraps around its edges
+func (w *World) Torus() bool {
+	return w.torus
+}
+
+// offset returns the shortest step from one position to another,
+// which on a torus may go across an edge
+func (w *World) offset(from, to Position) (int, int) {
+	return axisOffset(from.X, to.X, w.Width, w.torus), axisOffset(from.Y, to.Y, w.Height, w.torus)
+}
+
+func wrapAxis(v, size int) int {
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+// axisOffset returns the signed distance from a to b on an axis of the given size
+func axisOffset(a, b, size int, torus bool) int {
+	d := b - a
+	if torus {
+		if d > size/2 {
+			d -= size
+		} else if d < -size/2 {
+			d += size
+		}
+	}
+	return d
+}
+
+// axisSpans returns the ranges of buckets, of the given side length, covering the cells
+// within radius of center on one axis. On a torus a range crossing an edge is split in two
+func axisSpans(center, radius, size, bucketSize, buckets int, torus bool) [][2]int {
+	lo, hi := center-radius, center+radius
+	if !torus {
+		return [][2]int{{max(lo, 0) / bucketSize, min(hi/bucketSize, buckets-1)}}
+	}
+
+	var spans [][2]int
+	switch {
+	case 2*radius+1 >= size:
+		return [][2]int{{0, buckets - 1}}
+	case lo < 0:
+		spans = [][2]int{{0, hi / bucketSize}, {(lo + size) / bucketSize, buckets - 1}}
+	case hi >= size:
+		spans = [][2]int{{0, (hi - size) / bucketSize}, {lo / bucketSize, buckets - 1}}
+	default:
+		return [][2]int{{lo / bucketSize, hi / bucketSize}}
+	}
+
+	// Both ends may fall into the same bucket, which must only be visited once
+	if spans[0][1] >= spans[1][0] {
+		return [][2]int{{0, buckets - 1}}
+	}
+	return spans
+}
@@ -0,0 +1,166 @@
+package simulation
+
+import "math/rand/v2"
+
+// Burrow is a refuge dug into a cell. Animals of species using burrows hide in it
+// from the predators they see, out of their sight and reach, but can't eat or mate while hidden
+type Burrow struct {
+	Position  Position
+	Occupants int // Animals hiding in the burrow, at most BurrowCapacity
+}
+
+// AddBurrow digs a burrow on a cell, unless there already is one or the cell is outside
+// the world. It reports whether it dug one
+func (w *World) AddBurrow(x, y int) bool {
+	x, y, ok := w.Wrap(x, y)
+	if !ok || w.BurrowAt(x, y) != nil {
+		return false
+	}
+	w.Burrows = append(w.Burrows, &Burrow{Position: Position{X: x, Y: y}})
+	return true
+}
+
+// BurrowAt returns the burrow on a cell, or nil if there's none
+func (w *World) BurrowAt(x, y int) *Burrow {
+	for _, burrow := range w.Burrows {
+		if burrow.Position.X == x && burrow.Position.Y == y {
+			return burrow
+		}
+	}
+	return nil
+}
+
+// digBurrows digs BurrowCount burrows on random passable cells
+func (w *World) digBurrows() {
+	for len(w.Burrows) < w.Config.BurrowCount {
+		x, y := w.getRandomEmptyPosition()
+		w.AddBurrow(x, y)
+	}
+}
+
+// nearestBurrow returns the closest burrow within maxRange of pos that has room for
+// another animal, and its distance. Ties go to the lower position
+func (w *World) nearestBurrow(pos Position, maxRange int) (*Burrow, int, bool) {
+	var nearest *Burrow
+	minDistance := 0
+	for _, burrow := range w.Burrows {
+		if burrow.Occupants >= w.Config.BurrowCapacity {
+			continue
+		}
+		dx, dy := w.offset(pos, burrow.Position)
+		distance := abs(dx) + abs(dy)
+		if distance > maxRange {
+			continue
+		}
+		if nearest == nil || distance < minDistance || (distance == minDistance && burrow.Position.Less(nearest.Position)) {
+			nearest, minDistance = burrow, distance
+		}
+	}
+	return nearest, minDistance, nearest != nil
+}
+
+// planHide plans the way to the nearest burrow with room the animal sees, if its species
+// uses burrows. Next to the burrow it stays put, ready to hide. It reports false when
+// there's no such burrow or no way toward it
+func (a *Animal) planHide(world *World, budget int, rng *rand.Rand) (movePlan, bool) {
+	if !a.Species.UsesBurrows || len(world.Burrows) == 0 {
+		return movePlan{}, false
+	}
+	burrow, distance, found := world.nearestBurrow(a.Position, a.Traits.Vision)
+	if !found {
+		return movePlan{}, false
+	}
+	if distance <= 1 {
+		return movePlan{}, true
+	}
+
+	var plan movePlan
+	if budget > 0 {
+		plan = a.planChase(burrow.Position, world, budget)
+	} else {
+		plan = a.planDirectionalMove(burrow.Position, world, true, rng)
+	}
+	return plan, plan.OK
+}
+
+// hide takes an animal that sees a predator into a burrow with room on or next to its
+// cell, off the map. It reports whether the animal hid
+func (w *World) hide(a *Animal) bool {
+	if !a.Species.UsesBurrows || len(w.Burrows) == 0 {
+		return false
+	}
+	if _, found := w.nearest(a.Species.predators, a.Position, a.Traits.Vision); !found {
+		return false
+	}
+	burrow, _, found := w.nearestBurrow(a.Position, 1)
+	if !found {
+		return false
+	}
+
+	from := a.Position
+	w.vacate(a)
+	w.populationOf(a.Species).index.Remove(a)
+	a.Position = burrow.Position
+	a.Burrow = burrow
+	burrow.Occupants++
+	w.emit(Event{Kind: Hid, Animal: a, Position: burrow.Position, From: from})
+	return true
+}
+
+// stayHidden spends a tick of a hidden animal in its burrow. It uses as much energy as
+// moving and can't eat or conceive, but a pregnancy goes on, with the young born around
+// the burrow. The animal comes out onto the burrow's cell, or the first free cell next
+// to it, once it sees no predator. It returns the young born this tick
+func (w *World) stayHidden(a *Animal) []*Animal {
+	a.Energy -= a.moveCost(a.Species.EnergyLossPerMove)
+	if a.IsDead() {
+		return nil
+	}
+
+	litter := w.waitInBurrow(a)
+	if a.IsDead() {
+		return litter
+	}
+	if _, found := w.nearest(a.Species.predators, a.Position, a.Traits.Vision); found {
+		return litter
+	}
+
+	burrow := a.Burrow
+	for _, step := range append([]Position{{}}, neighbourSteps[:]...) {
+		x, y, ok := w.Wrap(burrow.Position.X+step.X, burrow.Position.Y+step.Y)
+		if !ok || w.IsPositionBlocked(x, y) {
+			continue
+		}
+		w.leaveBurrow(a)
+		a.Position = Position{X: x, Y: y}
+		w.occupancy[x][y] = a
+		w.populationOf(a.Species).index.Insert(a)
+		w.emit(Event{Kind: Emerged, Animal: a, Position: a.Position, From: burrow.Position})
+		break
+	}
+	return litter
+}
+
+// waitInBurrow passes the time of a hidden animal instead of eating and reproducing, on the
+// tick it hides and every tick it stays hidden. Its pregnancy goes on, and it returns the
+// young born this tick
+func (w *World) waitInBurrow(a *Animal) []*Animal {
+	a.TurnsSinceEaten++
+	a.TurnsSinceReproduction++
+	if a.Pregnancy == nil {
+		return nil
+	}
+	pregnancy, due := a.gestate()
+	if !due {
+		return nil
+	}
+	return w.born(a, a.giveBirth(pregnancy, w))
+}
+
+// leaveBurrow frees the place of a hidden animal in its burrow, doing nothing for animals on the map
+func (w *World) leaveBurrow(a *Animal) {
+	if a.Burrow != nil {
+		a.Burrow.Occupants--
+		a.Burrow = nil
+	}
+}
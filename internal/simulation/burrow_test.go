@@ -0,0 +1,50 @@
+package simulation
+
+import (
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"testing"
+)
+
+func TestPregnancyGoesOnInBurrow(t *testing.T) {
+	for _, workers := range []int{0, 2} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.WorldWidth, cfg.WorldHeight = 20, 20
+			cfg.ParallelWorkers = workers
+			world := NewWorld(cfg, 1)
+			world.AddBurrow(5, 5)
+
+			// The rabbit, pregnant before it sees the fox, hides on the first tick
+			rabbit := NewAnimal(world.Population("rabbit").Species, 5, 6, cfg)
+			rabbit.Energy = 100
+			rabbit.Pregnancy = &Pregnancy{TicksLeft: 3, LitterSize: 2, Traits: rabbit.Traits, CostLeft: 30}
+			world.AddAnimal(rabbit)
+			world.AddAnimal(NewAnimal(world.Population("fox").Species, 12, 5, cfg))
+
+			for tick := 1; tick <= 3; tick++ {
+				world.Update()
+				if rabbit.Burrow == nil {
+					t.Fatalf("the rabbit isn't hidden after tick %d", tick)
+				}
+				if births := world.Population("rabbit").LastTick.Births; (births != 0) != (tick == 3) {
+					t.Fatalf("%d young born on tick %d, expected them on tick 3", births, tick)
+				}
+			}
+
+			if rabbit.Pregnancy != nil || rabbit.TurnsSinceEaten != 3 || rabbit.TurnsSinceReproduction != 3 {
+				t.Fatalf("pregnancy %v, %d turns since eaten and %d since reproduction, expected no pregnancy and 3 turns",
+					rabbit.Pregnancy, rabbit.TurnsSinceEaten, rabbit.TurnsSinceReproduction)
+			}
+			young := world.Population("rabbit").Animals[1:]
+			if len(young) != 2 {
+				t.Fatalf("got %d young, expected 2", len(young))
+			}
+			for _, animal := range young {
+				if dx, dy := world.offset(animal.Position, Position{X: 5, Y: 5}); abs(dx) > 1 || abs(dy) > 1 {
+					t.Fatalf("young born at %v, away from the burrow", animal.Position)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package simulation
+
+import "fmt"
+
+// EventKind identifies what happened in an Event
+type EventKind int
+
+const (
+	Born EventKind = iota
+	Starved
+	Eaten
+	Moved
+	GrassRegrew
+	DiedOfAge
+	Hid
+	Emerged
+)
+
+var eventKindNames = [...]string{
+	Born:        "born",
+	Starved:     "starved",
+	Eaten:       "eaten",
+	Moved:       "moved",
+	GrassRegrew: "grass_regrew",
+	DiedOfAge:   "died_of_age",
+	Hid:         "hid",
+	Emerged:     "emerged",
+}
+
+func (k EventKind) String() string {
+	if k >= 0 && int(k) < len(eventKindNames) {
+		return eventKindNames[k]
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event describes a single change to the world during Update
+type Event struct {
+	Tick int
+	Kind EventKind
+
+	// Animal is the animal the event happened to, nil for grass events
+	Animal *Animal
+	// Other is the parent of a newborn or the predator of an eaten animal
+	Other *Animal
+
+	// Position is where the event happened, From is the previous cell of an animal that
+	// moved, hid in a burrow or came out of one
+	Position Position
+	From     Position
+}
+
+// Subscribe registers fn to be called synchronously for every event the world emits
+func (w *World) Subscribe(fn func(Event)) {
+	w.listeners = append(w.listeners, fn)
+}
+
+func (w *World) emit(event Event) {
+	if len(w.listeners) == 0 {
+		return
+	}
+
+	event.Tick = w.Tick
+	for _, listener := range w.listeners {
+		listener(event)
+	}
+}
+
+// ParseEventKind returns the kind with the given name, as printed by EventKind.String
+func ParseEventKind(name string) (EventKind, error) {
+	for kind, kindName := range eventKindNames {
+		if kindName == name {
+			return EventKind(kind), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event kind %q", name)
+}
@@ -20,7 +20,8 @@ func NewGrass(cfg *config.Config) *Grass {
 	}
 }
 
-func (g *Grass) Grow() {
+// Grow advances the regrowth timer and reports whether the grass regrew this tick
+func (g *Grass) Grow() bool {
 	if g.Amount < g.MaxAmount {
 		g.RegrowthTimer++
 
@@ -28,8 +29,10 @@ func (g *Grass) Grow() {
 		if g.RegrowthTimer >= g.Config.GrassRegrowthTimer {
 			g.Amount += g.GrowthRate
 			g.RegrowthTimer = 0
+			return true
 		}
 	}
+	return false
 }
 
 func (g *Grass) Eat(amount int) {
@@ -39,3 +42,9 @@ func (g *Grass) Eat(amount int) {
 		g.Amount = 0
 	}
 }
+
+// SetAmount sets the grass to amount, clamped to the valid range, and restarts regrowth
+func (g *Grass) SetAmount(amount int) {
+	g.Amount = max(0, min(amount, g.MaxAmount))
+	g.RegrowthTimer = 0
+}
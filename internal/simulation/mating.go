@@ -0,0 +1,84 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Sex of an animal. Animals are Asexual unless the world uses sexual reproduction
+type Sex uint8
+
+const (
+	Asexual Sex = iota
+	Female
+	Male
+)
+
+var sexNames = [...]string{
+	Asexual: "asexual",
+	Female:  "female",
+	Male:    "male",
+}
+
+func (s Sex) String() string {
+	if int(s) < len(sexNames) {
+		return sexNames[s]
+	}
+	return fmt.Sprintf("Sex(%d)", int(s))
+}
+
+// MarshalText writes the sex by name, so snapshots stay readable
+func (s Sex) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Sex) UnmarshalText(text []byte) error {
+	for sex, name := range sexNames {
+		if name == string(text) {
+			*s = Sex(sex)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sex %q", text)
+}
+
+// assignSex makes an animal placed in the world female or male at random when the
+// world uses sexual reproduction. Animals that already have a sex keep it
+func (w *World) assignSex(a *Animal) {
+	if w.Config.SexualReproduction && a.Sex == Asexual {
+		a.Sex = Female + Sex(w.rng.IntN(2))
+	}
+}
+
+// findMate returns the closest male of the female's species within its reproduction
+// range on both axes that is ready to mate: mature, past his reproduction cooldown
+// and with enough energy
+func findMate(female *Animal, index *SpatialIndex) (*Animal, bool) {
+	species := female.Species
+	return index.NearestWhere(female.Position, species.ReproductionRange, func(male *Animal) bool {
+		return male.Sex == Male && !male.IsDead() && male.Age >= species.MaturityAge &&
+			male.CanReproduce(species.ReproductionCooldown) && male.Energy >= male.Traits.ReproductionThreshold
+	})
+}
+
+// litterSize draws how many young are born at once, averaging mean.
+// "fixed" rounds the mean up or down at random to keep its average, while
+// "poisson" gives one young plus a Poisson distributed number of extra ones
+func litterSize(mean float64, distribution string, rng *rand.Rand) int {
+	if distribution == "poisson" {
+		// Knuth's method, fine for the small means litters have
+		limit, size, product := math.Exp(-(mean - 1)), 1, rng.Float64()
+		for product > limit {
+			size++
+			product *= rng.Float64()
+		}
+		return size
+	}
+
+	size := int(mean)
+	if fraction := mean - float64(size); fraction > 0 && rng.Float64() < fraction {
+		size++
+	}
+	return size
+}
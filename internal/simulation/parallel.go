@@ -0,0 +1,143 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// parallelTileSize is the side length, in cells, of the tiles handed to workers by the parallel update
+const parallelTileSize = 32
+
+// updateAnimalsParallel runs the animal phase of Update on a worker pool.
+// For each species in turn, workers plan the moves of every animal tile by tile
+// against the world as it is, then the plans are applied in slice order: an animal whose
+// target cell was taken by an earlier one stays where it is. Eating and births
+// change shared state, so they run sequentially once all moves are applied.
+// Every animal draws from its own generator, seeded from the world seed, tick and
+// animal ID, so the result doesn't depend on the number of workers or scheduling
+func (w *World) updateAnimalsParallel() [][]*Animal {
+	newborns := make([][]*Animal, len(w.Populations))
+
+	// Each species plans against the world as the species before it left it
+	for i, population := range w.Populations {
+		plans := planMoves(w, population.Animals)
+		// Animals that spent the tick in a burrow, or went into one, don't eat or breed
+		// with the others, but their pregnancies go on
+		inBurrow := make([]bool, len(population.Animals))
+		for j, animal := range population.Animals {
+			switch {
+			case animal.IsDead():
+			case animal.Burrow != nil:
+				newborns[i] = append(newborns[i], w.stayHidden(animal)...)
+				inBurrow[j] = true
+			default:
+				animal.finishMove(plans[j], w)
+				if inBurrow[j] = w.hide(animal); inBurrow[j] {
+					newborns[i] = append(newborns[i], w.waitInBurrow(animal)...)
+				}
+			}
+		}
+		for j, animal := range population.Animals {
+			if !animal.IsDead() && !inBurrow[j] {
+				newborns[i] = append(newborns[i], w.feedAndBreed(animal)...)
+			}
+		}
+	}
+
+	return newborns
+}
+
+// planMoves plans the next step of every living animal concurrently, grouping animals by tile.
+// Planning only reads the world, so workers never need to synchronize
+func planMoves(w *World, animals []*Animal) []movePlan {
+	plans := make([]movePlan, len(animals))
+
+	cols := (w.Width + parallelTileSize - 1) / parallelTileSize
+	rows := (w.Height + parallelTileSize - 1) / parallelTileSize
+	tiles := make([][]int, cols*rows)
+	for i, animal := range animals {
+		if animal.IsDead() || animal.Burrow != nil {
+			continue
+		}
+		pos := animal.Position
+		tile := (pos.Y/parallelTileSize)*cols + pos.X/parallelTileSize
+		tiles[tile] = append(tiles[tile], i)
+	}
+
+	// Each worker reseeds its own generator for every animal it plans
+	sources := make([]*rand.PCG, w.Config.ParallelWorkers)
+	rngs := make([]*rand.Rand, w.Config.ParallelWorkers)
+	for i := range sources {
+		sources[i] = rand.NewPCG(0, 0)
+		rngs[i] = rand.New(sources[i])
+	}
+
+	w.parallelFor(len(tiles), func(worker, tile int) {
+		for _, i := range tiles[tile] {
+			sources[worker].Seed(w.animalSeed(animals[i].ID))
+			plans[i] = animals[i].planMove(w, rngs[worker])
+		}
+	})
+
+	return plans
+}
+
+// growGrassParallel grows grass in vertical strips on the worker pool, then emits
+// the regrowth events in the same column order as the sequential update
+func (w *World) growGrassParallel() {
+	strips := (w.Width + parallelTileSize - 1) / parallelTileSize
+	regrown := make([][]Position, strips)
+	collect := len(w.listeners) > 0
+
+	w.parallelFor(strips, func(_, strip int) {
+		fromX := strip * parallelTileSize
+		toX := min(fromX+parallelTileSize, w.Width)
+		w.growGrass(fromX, toX, func(pos Position) {
+			if collect {
+				regrown[strip] = append(regrown[strip], pos)
+			}
+		})
+	})
+
+	for _, positions := range regrown {
+		for _, pos := range positions {
+			w.emit(Event{Kind: GrassRegrew, Position: pos})
+		}
+	}
+}
+
+// parallelFor calls job for every index in [0, n) using ParallelWorkers goroutines.
+// job also receives the number of the worker running it, from 0 to ParallelWorkers-1
+func (w *World) parallelFor(n int, job func(worker, i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for worker := range w.Config.ParallelWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				job(worker, i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// animalSeed derives the seed of an animal's own generator for the current tick
+func (w *World) animalSeed(id int) (uint64, uint64) {
+	return splitmix64(w.Seed + uint64(w.Tick)), splitmix64(uint64(id))
+}
+
+// splitmix64 scrambles x so that nearby inputs give unrelated seeds
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,27 @@
+package simulation
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestParallelUpdateIndependentOfWorkers(t *testing.T) {
+	for name, cfg := range testConfigs() {
+		t.Run(name, func(t *testing.T) {
+			var counts []int
+			var state []byte
+			for _, workers := range []int{1, 2, 8} {
+				cfg := cloneConfig(cfg)
+				cfg.ParallelWorkers = workers
+				workerCounts, workerState := run(t, cfg, 7, 200)
+
+				if counts == nil {
+					counts, state = workerCounts, workerState
+				} else if !slices.Equal(counts, workerCounts) || !bytes.Equal(state, workerState) {
+					t.Fatalf("the run with %d workers differs from the one with 1", workers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package simulation
+
+import (
+	"container/heap"
+	"math/rand/v2"
+)
+
+// neighbourSteps are the moves animals can make, in the order searches try them
+var neighbourSteps = [...]Position{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
+
+// pathNode is a cell reached by a search, remembering the step it was reached from
+type pathNode struct {
+	pos       Position
+	parent    int // index of the previous node, -1 for the start
+	steps     int
+	heuristic int
+}
+
+// planChase plans the first step of a short path around obstacles and other
+// animals to a cell next to target, using A* limited to expanding budget cells.
+// If the target can't be reached within the budget, it heads for the searched cell
+// closest to the target
+func (a *Animal) planChase(target Position, world *World, budget int) movePlan {
+	distanceToTarget := func(pos Position) int {
+		dx, dy := world.offset(pos, target)
+		return abs(dx) + abs(dy)
+	}
+
+	nodes := []pathNode{{pos: a.Position, parent: -1, heuristic: distanceToTarget(a.Position)}}
+	if nodes[0].heuristic <= 1 {
+		return movePlan{} // Already next to the target
+	}
+	seen := map[Position]bool{a.Position: true}
+	open := &pathQueue{nodes: &nodes, items: []int{0}}
+	best := 0
+
+	for expanded := 0; open.Len() > 0 && expanded < budget; expanded++ {
+		current := heap.Pop(open).(int)
+		node := nodes[current]
+		if node.heuristic < nodes[best].heuristic {
+			best = current
+		}
+		if node.heuristic <= 1 {
+			break
+		}
+
+		for _, step := range neighbourSteps {
+			x, y, ok := world.Wrap(node.pos.X+step.X, node.pos.Y+step.Y)
+			next := Position{X: x, Y: y}
+			if !ok || seen[next] || world.IsPositionBlocked(x, y) {
+				continue
+			}
+			seen[next] = true
+			nodes = append(nodes, pathNode{pos: next, parent: current, steps: node.steps + 1, heuristic: distanceToTarget(next)})
+			heap.Push(open, len(nodes)-1)
+		}
+	}
+
+	return firstStep(nodes, best)
+}
+
+// planFlight plans the first step toward the cell that is farthest from every
+// threat, among the cells reachable by expanding at most budget cells
+func (a *Animal) planFlight(threats []Position, world *World, budget int, rng *rand.Rand) movePlan {
+	if rng.Float64() < a.Config.ChanceToStayStillWhenFleeing {
+		return movePlan{}
+	}
+
+	safety := func(pos Position) int {
+		nearest := -1
+		for _, threat := range threats {
+			dx, dy := world.offset(pos, threat)
+			if distance := abs(dx) + abs(dy); nearest < 0 || distance < nearest {
+				nearest = distance
+			}
+		}
+		return nearest
+	}
+
+	// Breadth-first, so among equally safe cells the closest one wins
+	nodes := []pathNode{{pos: a.Position, parent: -1, heuristic: safety(a.Position)}}
+	seen := map[Position]bool{a.Position: true}
+	best := 0
+
+	for current := 0; current < len(nodes) && current < budget; current++ {
+		node := nodes[current]
+		if node.heuristic > nodes[best].heuristic {
+			best = current
+		}
+
+		for _, step := range neighbourSteps {
+			x, y, ok := world.Wrap(node.pos.X+step.X, node.pos.Y+step.Y)
+			next := Position{X: x, Y: y}
+			if !ok || seen[next] || world.IsPositionBlocked(x, y) {
+				continue
+			}
+			seen[next] = true
+			nodes = append(nodes, pathNode{pos: next, parent: current, steps: node.steps + 1, heuristic: safety(next)})
+		}
+	}
+
+	return firstStep(nodes, best)
+}
+
+// firstStep walks back from a search node to the step taken from the start
+func firstStep(nodes []pathNode, node int) movePlan {
+	if nodes[node].parent < 0 {
+		return movePlan{} // Staying put is best
+	}
+	for nodes[node].parent != 0 {
+		node = nodes[node].parent
+	}
+	return movePlan{To: nodes[node].pos, OK: true}
+}
+
+// pathQueue orders search nodes by estimated path length, then by closeness to
+// the target, then by the order they were found, so searches are deterministic
+type pathQueue struct {
+	nodes *[]pathNode
+	items []int
+}
+
+func (q *pathQueue) Len() int { return len(q.items) }
+
+func (q *pathQueue) Less(i, j int) bool {
+	a, b := (*q.nodes)[q.items[i]], (*q.nodes)[q.items[j]]
+	if a.steps+a.heuristic != b.steps+b.heuristic {
+		return a.steps+a.heuristic < b.steps+b.heuristic
+	}
+	if a.heuristic != b.heuristic {
+		return a.heuristic < b.heuristic
+	}
+	return q.items[i] < q.items[j]
+}
+
+func (q *pathQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *pathQueue) Push(x any) { q.items = append(q.items, x.(int)) }
+
+func (q *pathQueue) Pop() any {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
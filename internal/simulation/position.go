@@ -4,3 +4,11 @@ type Position struct {
 	X int
 	Y int
 }
+
+// Less orders positions row by row, giving a stable order for tie-breaking
+func (p Position) Less(other Position) bool {
+	if p.Y != other.Y {
+		return p.Y < other.Y
+	}
+	return p.X < other.X
+}
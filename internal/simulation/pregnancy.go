@@ -0,0 +1,42 @@
+package simulation
+
+// Pregnancy is the state of an animal carrying young until they're born
+type Pregnancy struct {
+	TicksLeft  int
+	LitterSize int
+	Traits     Traits // Inherited from the parents, mutated separately for each young at birth
+	CostLeft   int    // Energy the mother still pays before giving birth
+}
+
+// conceive starts a pregnancy lasting gestation ticks, during which the mother pays cost
+// bit by bit. With no gestation the mother pays at once and the litter is due right away
+func (a *Animal) conceive(gestation, litterSize, cost int, traits Traits) (*Pregnancy, bool) {
+	pregnancy := &Pregnancy{TicksLeft: gestation, LitterSize: litterSize, Traits: traits, CostLeft: cost}
+	if gestation <= 0 {
+		a.Energy -= cost
+		return pregnancy, true
+	}
+	a.Pregnancy = pregnancy
+	return nil, false
+}
+
+// gestate advances the animal's pregnancy by a tick, paying this tick's share of its
+// energy cost, and returns the pregnancy when it's due. A mother that runs out of
+// energy loses her litter
+func (a *Animal) gestate() (*Pregnancy, bool) {
+	pregnancy := a.Pregnancy
+	payment := pregnancy.CostLeft / pregnancy.TicksLeft
+	a.Energy -= payment
+	pregnancy.CostLeft -= payment
+	pregnancy.TicksLeft--
+
+	if a.IsDead() {
+		a.Pregnancy = nil
+		return nil, false
+	}
+	if pregnancy.TicksLeft > 0 {
+		return nil, false
+	}
+	a.Pregnancy = nil
+	return pregnancy, true
+}
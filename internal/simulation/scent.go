@@ -0,0 +1,116 @@
+package simulation
+
+import "math/rand/v2"
+
+// updateScent lets every living animal mark its cell with the scent of its species,
+// then spreads each species' scent to neighbouring cells and lets it fade.
+// Scent doesn't spread into water or rock, and only fades, never leaks, at the world's edges
+func (w *World) updateScent() {
+	for _, population := range w.Populations {
+		if population.Scent == nil {
+			population.Scent = w.newScentGrid()
+			population.scentBuffer = w.newScentGrid()
+		}
+		for _, animal := range population.Animals {
+			if animal.Burrow != nil {
+				continue // Hidden animals leave no scent
+			}
+			population.Scent[animal.Position.X][animal.Position.Y] += w.Config.ScentDeposit
+		}
+
+		if w.Config.ParallelWorkers > 0 {
+			strips := (w.Width + parallelTileSize - 1) / parallelTileSize
+			w.parallelFor(strips, func(_, strip int) {
+				fromX := strip * parallelTileSize
+				w.spreadScent(population, fromX, min(fromX+parallelTileSize, w.Width))
+			})
+		} else {
+			w.spreadScent(population, 0, w.Width)
+		}
+		population.Scent, population.scentBuffer = population.scentBuffer, population.Scent
+	}
+}
+
+// spreadScent writes the diffused and decayed scent of columns fromX up to toX into the
+// population's scent buffer. Each cell passes a quarter of ScentDiffusion of its scent to
+// every passable neighbour, so diffusion alone never creates or destroys scent
+func (w *World) spreadScent(population *Population, fromX, toX int) {
+	share := w.Config.ScentDiffusion / 4
+	keep := 1 - w.Config.ScentDecay
+
+	for x := fromX; x < toX; x++ {
+		for y := 0; y < w.Height; y++ {
+			if !w.TerrainGrid[x][y].Passable() {
+				population.scentBuffer[x][y] = 0
+				continue
+			}
+
+			scent := population.Scent[x][y]
+			next := scent
+			for _, step := range neighbourSteps {
+				nx, ny, ok := w.Wrap(x+step.X, y+step.Y)
+				if !ok || !w.TerrainGrid[nx][ny].Passable() {
+					continue
+				}
+				next += share * (population.Scent[nx][ny] - scent)
+			}
+			population.scentBuffer[x][y] = next * keep
+		}
+	}
+}
+
+// newScentGrid returns a scent-free grid the size of the world, indexed [x][y]
+func (w *World) newScentGrid() [][]float64 {
+	grid := make([][]float64, w.Width)
+	for x := range grid {
+		grid[x] = make([]float64, w.Height)
+	}
+	return grid
+}
+
+// ScentAt returns the scent of the given species on a cell, 0 if scent is disabled
+func (w *World) ScentAt(species []*Species, pos Position) float64 {
+	total := 0.0
+	for _, s := range species {
+		if scent := w.populationOf(s).Scent; scent != nil {
+			total += scent[pos.X][pos.Y]
+		}
+	}
+	return total
+}
+
+// planScentMove picks the free neighbouring cell where the scent of the given species is
+// strongest, or weakest if away is set, provided it's stronger, or weaker, than on the
+// animal's own cell, and the scent there is at least ScentThreshold.
+// Ties go to the first cell in neighbourSteps order
+func (a *Animal) planScentMove(species []*Species, world *World, away bool) movePlan {
+	here := world.ScentAt(species, a.Position)
+	if here < a.Config.ScentThreshold && !away {
+		here = a.Config.ScentThreshold
+	}
+
+	best, bestScent := movePlan{}, here
+	for _, step := range neighbourSteps {
+		plan := planStep(a.Position.X+step.X, a.Position.Y+step.Y, world)
+		if !plan.OK {
+			continue
+		}
+		scent := world.ScentAt(species, plan.To)
+		if (away && scent < bestScent) || (!away && scent > bestScent) {
+			best, bestScent = plan, scent
+		}
+	}
+	return best
+}
+
+// planScentFlight steps away from predator scent of at least ScentThreshold on the
+// animal's own cell, stumbling as often as fleeing from a predator in sight
+func (a *Animal) planScentFlight(world *World, rng *rand.Rand) movePlan {
+	if world.ScentAt(a.Species.predators, a.Position) < a.Config.ScentThreshold {
+		return movePlan{}
+	}
+	if rng.Float64() < a.Config.ChanceToStayStillWhenFleeing {
+		return movePlan{}
+	}
+	return a.planScentMove(a.Species.predators, world, true)
+}
@@ -0,0 +1,255 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"io"
+)
+
+// snapshotVersion is bumped whenever the snapshot format changes incompatibly.
+// Version 1 snapshots, which only had foxes and rabbits, can still be loaded
+const snapshotVersion = 2
+
+// snapshot is the serialized form of a World, holding everything needed to resume it exactly
+type snapshot struct {
+	Version int
+	Width   int
+	Height  int
+	Tick    int
+	Seed    uint64
+	NextID  int
+	RNG     []byte
+	Config  *config.Config
+	Grass   []grassState // column by column, in the same [x][y] order as GrassGrid
+	Burrows []Position   `json:",omitempty"`
+
+	Populations []populationState
+
+	// Version 1 kept foxes and rabbits apart
+	Foxes   []animalState `json:",omitempty"`
+	Rabbits []animalState `json:",omitempty"`
+}
+
+type populationState struct {
+	Species string
+	Animals []animalState
+	Scent   []float64 `json:",omitempty"` // Column by column like Grass, left out while scent is disabled
+}
+
+type grassState struct {
+	Amount        int
+	RegrowthTimer int
+	Terrain       Terrain `json:",omitempty"` // Left out for meadow
+}
+
+type animalState struct {
+	ID                     int
+	X                      int
+	Y                      int
+	Energy                 int
+	TurnsSinceEaten        int
+	TurnsSinceReproduction int
+	Traits                 Traits
+	Age                    int
+	Lifespan               int
+	Sex                    Sex        `json:",omitempty"`
+	Pregnancy              *Pregnancy `json:",omitempty"`
+	Hidden                 bool       `json:",omitempty"` // In the burrow at X, Y
+}
+
+func saveAnimal(a *Animal) animalState {
+	return animalState{
+		ID:                     a.ID,
+		X:                      a.Position.X,
+		Y:                      a.Position.Y,
+		Energy:                 a.Energy,
+		TurnsSinceEaten:        a.TurnsSinceEaten,
+		TurnsSinceReproduction: a.TurnsSinceReproduction,
+		Traits:                 a.Traits,
+		Age:                    a.Age,
+		Lifespan:               a.Lifespan,
+		Sex:                    a.Sex,
+		Pregnancy:              a.Pregnancy,
+		Hidden:                 a.Burrow != nil,
+	}
+}
+
+func (s animalState) restore(a *Animal) {
+	a.ID = s.ID
+	a.Energy = s.Energy
+	a.TurnsSinceEaten = s.TurnsSinceEaten
+	a.TurnsSinceReproduction = s.TurnsSinceReproduction
+	a.Age = s.Age
+	a.Lifespan = s.Lifespan
+	a.Sex = s.Sex
+	a.Pregnancy = s.Pregnancy
+
+	// Snapshots from before traits existed keep the defaults
+	if s.Traits != (Traits{}) {
+		a.Traits = s.Traits
+	}
+}
+
+// Save writes a snapshot of the world as JSON. Loading it with LoadWorld
+// resumes the simulation exactly where it was saved
+func (w *World) Save(out io.Writer) error {
+	rngState, err := w.src.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	snap := snapshot{
+		Version: snapshotVersion,
+		Width:   w.Width,
+		Height:  w.Height,
+		Tick:    w.Tick,
+		Seed:    w.Seed,
+		NextID:  w.nextID,
+		RNG:     rngState,
+		Config:  w.Config,
+		Grass:   make([]grassState, 0, w.Width*w.Height),
+		Burrows: make([]Position, 0, len(w.Burrows)),
+
+		Populations: make([]populationState, 0, len(w.Populations)),
+	}
+
+	for x := 0; x < w.Width; x++ {
+		for y := 0; y < w.Height; y++ {
+			grass := w.GrassGrid[x][y]
+			snap.Grass = append(snap.Grass, grassState{
+				Amount:        grass.Amount,
+				RegrowthTimer: grass.RegrowthTimer,
+				Terrain:       w.TerrainGrid[x][y],
+			})
+		}
+	}
+
+	for _, burrow := range w.Burrows {
+		snap.Burrows = append(snap.Burrows, burrow.Position)
+	}
+
+	for _, population := range w.Populations {
+		state := populationState{Species: population.Species.Name, Animals: make([]animalState, 0, len(population.Animals))}
+		for _, animal := range population.Animals {
+			state.Animals = append(state.Animals, saveAnimal(animal))
+		}
+		for _, column := range population.Scent {
+			state.Scent = append(state.Scent, column...)
+		}
+		snap.Populations = append(snap.Populations, state)
+	}
+
+	return json.NewEncoder(out).Encode(snap)
+}
+
+// LoadWorld restores a world written by World.Save, with overrides applied to the config
+// it was running with. Overrides changing the world size are rejected
+func LoadWorld(in io.Reader, overrides config.Overrides) (*World, error) {
+	// Fields added to the config since the snapshot was written keep their defaults
+	snap := snapshot{Config: config.NewConfig()}
+	if err := json.NewDecoder(in).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	if snap.Version == 1 {
+		snap.Populations = []populationState{{Species: "fox", Animals: snap.Foxes}, {Species: "rabbit", Animals: snap.Rabbits}}
+	} else if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snap.Version, snapshotVersion)
+	}
+	if snap.Config == nil {
+		return nil, errors.New("snapshot has no config")
+	}
+	if err := snap.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("snapshot config: %w", err)
+	}
+	if err := overrides.Apply(snap.Config); err != nil {
+		return nil, err
+	}
+	if snap.Width != snap.Config.WorldWidth || snap.Height != snap.Config.WorldHeight {
+		return nil, fmt.Errorf("snapshot size %dx%d doesn't match its config (%dx%d)",
+			snap.Width, snap.Height, snap.Config.WorldWidth, snap.Config.WorldHeight)
+	}
+	if len(snap.Grass) != snap.Width*snap.Height {
+		return nil, fmt.Errorf("snapshot has %d grass cells, expected %d", len(snap.Grass), snap.Width*snap.Height)
+	}
+
+	world := NewWorld(snap.Config, snap.Seed)
+	world.Tick = snap.Tick
+	world.nextID = snap.NextID
+	if err := world.src.UnmarshalBinary(snap.RNG); err != nil {
+		return nil, fmt.Errorf("snapshot RNG state: %w", err)
+	}
+
+	for x := 0; x < world.Width; x++ {
+		for y := 0; y < world.Height; y++ {
+			state := snap.Grass[x*world.Height+y]
+			world.SetTerrain(x, y, state.Terrain)
+			world.GrassGrid[x][y].Amount = state.Amount
+			world.GrassGrid[x][y].RegrowthTimer = state.RegrowthTimer
+		}
+	}
+
+	for _, pos := range snap.Burrows {
+		if !world.AddBurrow(pos.X, pos.Y) {
+			return nil, fmt.Errorf("snapshot burrow at (%d, %d) is out of bounds or dug twice", pos.X, pos.Y)
+		}
+	}
+
+	// Animals are restored in the world's species order, whatever the order in the snapshot
+	states := make(map[string]populationState, len(snap.Populations))
+	for _, state := range snap.Populations {
+		if world.Population(state.Species) == nil {
+			return nil, fmt.Errorf("snapshot has animals of unknown species %q", state.Species)
+		}
+		if state.Scent != nil && len(state.Scent) != snap.Width*snap.Height {
+			return nil, fmt.Errorf("snapshot has %d %s scent cells, expected %d", len(state.Scent), state.Species, snap.Width*snap.Height)
+		}
+		states[state.Species] = state
+	}
+
+	for _, population := range world.Populations {
+		species := population.Species
+		if scent := states[species.Name].Scent; scent != nil {
+			population.Scent = world.newScentGrid()
+			population.scentBuffer = world.newScentGrid()
+			for x := range population.Scent {
+				copy(population.Scent[x], scent[x*world.Height:])
+			}
+		}
+		for _, state := range states[species.Name].Animals {
+			if p := state.Pregnancy; p != nil && (p.TicksLeft <= 0 || p.LitterSize < 0 || p.CostLeft < 0) {
+				return nil, fmt.Errorf("snapshot %s #%d has an invalid pregnancy: %d ticks left, %d young, %d energy to pay",
+					species.Name, state.ID, p.TicksLeft, p.LitterSize, p.CostLeft)
+			}
+			// Checked before IsPositionBlocked, which wraps coordinates around a torus
+			if state.X < 0 || state.X >= world.Width || state.Y < 0 || state.Y >= world.Height {
+				return nil, fmt.Errorf("snapshot %s at (%d, %d) is out of bounds", species.Name, state.X, state.Y)
+			}
+			if state.Hidden {
+				burrow := world.BurrowAt(state.X, state.Y)
+				if burrow == nil {
+					return nil, fmt.Errorf("snapshot %s hides in a burrow at (%d, %d) that doesn't exist", species.Name, state.X, state.Y)
+				}
+				if burrow.Occupants >= world.Config.BurrowCapacity {
+					return nil, fmt.Errorf("snapshot burrow at (%d, %d) holds more than %d animals", state.X, state.Y, world.Config.BurrowCapacity)
+				}
+				animal := NewAnimal(species, state.X, state.Y, world.Config)
+				state.restore(animal)
+				animal.Burrow = burrow
+				burrow.Occupants++
+				population.Animals = append(population.Animals, animal)
+				continue
+			}
+			if world.IsPositionBlocked(state.X, state.Y) {
+				return nil, fmt.Errorf("snapshot %s at (%d, %d) is on a blocked cell", species.Name, state.X, state.Y)
+			}
+			animal := NewAnimal(species, state.X, state.Y, world.Config)
+			state.restore(animal)
+			world.AddAnimal(animal)
+		}
+	}
+
+	return world, nil
+}
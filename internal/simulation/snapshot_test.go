@@ -0,0 +1,144 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResumeMatchesUninterruptedRun(t *testing.T) {
+	for name, cfg := range testConfigs() {
+		for _, workers := range []int{0, 2} {
+			cfg := cloneConfig(cfg)
+			cfg.ParallelWorkers = workers
+
+			t.Run(fmt.Sprintf("%s with %d workers", name, workers), func(t *testing.T) {
+				counts, state := run(t, cfg, 7, 200)
+
+				firstCounts, saved := run(t, cfg, 7, 100)
+				world, err := LoadWorld(bytes.NewReader(saved), nil)
+				if err != nil {
+					t.Fatalf("loading snapshot: %s", err)
+				}
+				world.Config.ParallelWorkers = workers
+				restCounts, resumedState := advance(t, world, 100)
+
+				if !slices.Equal(counts, append(firstCounts, restCounts...)) || !bytes.Equal(state, resumedState) {
+					t.Fatalf("resuming at tick 100 with %d workers differs from an uninterrupted run", workers)
+				}
+			})
+		}
+	}
+}
+
+func TestLoadRejectsInvalidPregnancies(t *testing.T) {
+	cfg := cloneConfig(testConfigs()["features"])
+	_, saved := run(t, cfg, 7, 50)
+
+	for name, pregnancy := range map[string]string{
+		"no ticks left":   `"Pregnancy":{"TicksLeft":0,"LitterSize":2,"CostLeft":10}`,
+		"negative litter": `"Pregnancy":{"TicksLeft":2,"LitterSize":-1,"CostLeft":10}`,
+		"negative cost":   `"Pregnancy":{"TicksLeft":2,"LitterSize":2,"CostLeft":-10}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Make the first saved animal pregnant
+			corrupted := bytes.Replace(saved, []byte(`"Traits":`), []byte(pregnancy+`,"Traits":`), 1)
+			if _, err := LoadWorld(bytes.NewReader(corrupted), nil); err == nil || !strings.Contains(err.Error(), "invalid pregnancy") {
+				t.Fatalf("expected an invalid pregnancy error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestLoadOldSnapshots loads snapshots saved by earlier versions, whose configs lack
+// the fields added since. Those fields must take their defaults
+func TestLoadOldSnapshots(t *testing.T) {
+	for _, test := range []struct {
+		path            string
+		foxes, rabbits  int
+		pregnantAnimals int
+	}{
+		{"testdata/snapshot-v1.json", 6, 22, 0},
+		{"testdata/snapshot-v1-gestation.json", 4, 17, 1},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			file, err := os.Open(test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			world, err := LoadWorld(file, nil)
+			if err != nil {
+				t.Fatalf("loading snapshot: %s", err)
+			}
+
+			defaults := config.NewConfig()
+			if world.Config.Senses != defaults.Senses || world.Config.BurrowCapacity != defaults.BurrowCapacity ||
+				world.Config.LitterSizeDistribution != defaults.LitterSizeDistribution {
+				t.Errorf("fields missing from the snapshot config don't have their defaults")
+			}
+
+			foxes, rabbits := world.Population("fox").Animals, world.Population("rabbit").Animals
+			if len(foxes) != test.foxes || len(rabbits) != test.rabbits {
+				t.Errorf("got %d foxes and %d rabbits, expected %d and %d", len(foxes), len(rabbits), test.foxes, test.rabbits)
+			}
+			pregnant := 0
+			for _, animal := range append(foxes, rabbits...) {
+				if animal.Pregnancy != nil {
+					pregnant++
+				}
+			}
+			if pregnant != test.pregnantAnimals {
+				t.Errorf("got %d pregnant animals, expected %d", pregnant, test.pregnantAnimals)
+			}
+
+			for range 50 {
+				world.Update()
+			}
+		})
+	}
+}
+
+func TestLoadRejectsAnimalsOutOfBounds(t *testing.T) {
+	for _, torus := range []bool{false, true} {
+		cfg := config.NewConfig()
+		cfg.WorldWidth, cfg.WorldHeight = 10, 10
+		cfg.InitialFoxes, cfg.InitialRabbits = 1, 1
+		cfg.Torus = torus
+		world := NewWorld(cfg, 1)
+		world.AddAnimal(NewAnimal(world.Population("rabbit").Species, 3, 4, cfg))
+
+		corrupted := bytes.Replace(save(t, world), []byte(`"X":3`), []byte(`"X":15`), 1)
+		if _, err := LoadWorld(bytes.NewReader(corrupted), nil); err == nil || !strings.Contains(err.Error(), "out of bounds") {
+			t.Fatalf("expected an out of bounds error with torus %t, got %v", torus, err)
+		}
+	}
+}
+
+func TestLoadRejectsOvercrowdedBurrows(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.WorldWidth, cfg.WorldHeight = 20, 20
+	world := NewWorld(cfg, 1)
+	world.AddBurrow(5, 5)
+	world.AddAnimal(NewAnimal(world.Population("fox").Species, 8, 5, cfg))
+	for _, y := range []int{4, 6} {
+		rabbit := NewAnimal(world.Population("rabbit").Species, 5, y, cfg)
+		world.AddAnimal(rabbit)
+		if !world.hide(rabbit) {
+			t.Fatal("the rabbit didn't hide from the fox")
+		}
+	}
+
+	saved := save(t, world)
+	if _, err := LoadWorld(bytes.NewReader(saved), config.Overrides{"BurrowCapacity=2"}); err != nil {
+		t.Fatalf("loading a full burrow: %s", err)
+	}
+	if _, err := LoadWorld(bytes.NewReader(saved), config.Overrides{"BurrowCapacity=1"}); err == nil || !strings.Contains(err.Error(), "holds more than") {
+		t.Fatalf("expected an overcrowded burrow error, got %v", err)
+	}
+}
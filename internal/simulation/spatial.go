@@ -0,0 +1,158 @@
+package simulation
+
+// spatialCellSize is the side length, in world cells, of one spatial index bucket
+const spatialCellSize = 8
+
+// SpatialIndex buckets the animals of one species into square cells of the world,
+// so range queries only visit the buckets around the queried position
+type SpatialIndex struct {
+	cols    int
+	rows    int
+	buckets [][]*Animal
+
+	// On a torus, queries near an edge also look across it
+	width  int
+	height int
+	torus  bool
+}
+
+func NewSpatialIndex(width, height int, torus bool) *SpatialIndex {
+	cols := (width + spatialCellSize - 1) / spatialCellSize
+	rows := (height + spatialCellSize - 1) / spatialCellSize
+	return &SpatialIndex{
+		cols:    cols,
+		rows:    rows,
+		buckets: make([][]*Animal, cols*rows),
+		width:   width,
+		height:  height,
+		torus:   torus,
+	}
+}
+
+// bucketOf returns the index of the bucket containing pos
+func (s *SpatialIndex) bucketOf(pos Position) int {
+	return (pos.Y/spatialCellSize)*s.cols + pos.X/spatialCellSize
+}
+
+func (s *SpatialIndex) Insert(animal *Animal) {
+	b := s.bucketOf(animal.Position)
+	s.buckets[b] = append(s.buckets[b], animal)
+}
+
+// Remove drops the animal from the bucket of its current position
+func (s *SpatialIndex) Remove(animal *Animal) {
+	s.removeFrom(s.bucketOf(animal.Position), animal)
+}
+
+func (s *SpatialIndex) removeFrom(b int, animal *Animal) {
+	bucket := s.buckets[b]
+	for i, other := range bucket {
+		if other == animal {
+			// Keep bucket order stable so queries stay deterministic for a given seed
+			s.buckets[b] = append(bucket[:i], bucket[i+1:]...)
+			return
+		}
+	}
+}
+
+// Move updates the index after the animal stepped from one position to another.
+// It must be called with the animal's previous position
+func (s *SpatialIndex) Move(animal *Animal, from, to Position) {
+	oldBucket, newBucket := s.bucketOf(from), s.bucketOf(to)
+	if oldBucket == newBucket {
+		return
+	}
+	s.removeFrom(oldBucket, animal)
+	s.buckets[newBucket] = append(s.buckets[newBucket], animal)
+}
+
+// forEachInRange calls visit for every animal in the buckets overlapping the
+// square of the given radius around pos, stopping early if visit returns false
+func (s *SpatialIndex) forEachInRange(pos Position, radius int, visit func(*Animal) bool) {
+	colSpans := axisSpans(pos.X, radius, s.width, spatialCellSize, s.cols, s.torus)
+	rowSpans := axisSpans(pos.Y, radius, s.height, spatialCellSize, s.rows, s.torus)
+
+	for _, rows := range rowSpans {
+		for row := rows[0]; row <= rows[1]; row++ {
+			for _, cols := range colSpans {
+				for col := cols[0]; col <= cols[1]; col++ {
+					for _, animal := range s.buckets[row*s.cols+col] {
+						if !visit(animal) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// distance returns the distance between two positions on both axes, across the edges on a torus
+func (s *SpatialIndex) distance(a, b Position) (int, int) {
+	return abs(axisOffset(a.X, b.X, s.width, s.torus)), abs(axisOffset(a.Y, b.Y, s.height, s.torus))
+}
+
+// Nearest returns the animal closest to pos by Manhattan distance within maxRange
+func (s *SpatialIndex) Nearest(pos Position, maxRange int) (*Animal, bool) {
+	var nearest *Animal
+	foundAnimal := false
+	minDistance := maxRange + 1
+
+	s.forEachInRange(pos, maxRange, func(animal *Animal) bool {
+		dx, dy := s.distance(pos, animal.Position)
+
+		// Ties go to the lower position so the result doesn't depend on bucket order
+		distance := dx + dy
+		if distance < minDistance || (foundAnimal && distance == minDistance && animal.Position.Less(nearest.Position)) {
+			nearest = animal
+			minDistance = distance
+			foundAnimal = true
+		}
+		return true
+	})
+
+	return nearest, foundAnimal
+}
+
+// AnyWithin reports whether an animal other than self is within range on both axes
+func (s *SpatialIndex) AnyWithin(self *Animal, range_ int) bool {
+	pos := self.Position
+	found := false
+
+	s.forEachInRange(pos, range_, func(other *Animal) bool {
+		if other == self {
+			return true
+		}
+
+		dx, dy := s.distance(pos, other.Position)
+		found = dx <= range_ && dy <= range_
+		return !found
+	})
+
+	return found
+}
+
+// NearestWhere returns the animal closest to pos by Manhattan distance among those
+// within maxRange on both axes that accept approves of
+func (s *SpatialIndex) NearestWhere(pos Position, maxRange int, accept func(*Animal) bool) (*Animal, bool) {
+	var nearest *Animal
+	foundAnimal := false
+	minDistance := 0
+
+	s.forEachInRange(pos, maxRange, func(animal *Animal) bool {
+		dx, dy := s.distance(pos, animal.Position)
+		if dx > maxRange || dy > maxRange || !accept(animal) {
+			return true
+		}
+
+		distance := dx + dy
+		if !foundAnimal || distance < minDistance || (distance == minDistance && animal.Position.Less(nearest.Position)) {
+			nearest = animal
+			minDistance = distance
+			foundAnimal = true
+		}
+		return true
+	})
+
+	return nearest, foundAnimal
+}
@@ -0,0 +1,40 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// BenchmarkNearest compares the spatial index with scanning every animal,
+// the way nearest animals were found before the index
+func BenchmarkNearest(b *testing.B) {
+	cfg := largeWorldConfig()
+	world := NewWorld(cfg, 1)
+	world.Initialize()
+	rabbits := world.Population("rabbit")
+
+	rng := rand.New(rand.NewPCG(1, 1))
+	queries := make([]Position, 1024)
+	for i := range queries {
+		queries[i] = Position{X: rng.IntN(cfg.WorldWidth), Y: rng.IntN(cfg.WorldHeight)}
+	}
+	maxRange := cfg.FoxFollowRabbitRange
+
+	b.Run("index", func(b *testing.B) {
+		for i := range b.N {
+			rabbits.index.Nearest(queries[i%len(queries)], maxRange)
+		}
+	})
+
+	b.Run("scan", func(b *testing.B) {
+		for i := range b.N {
+			pos := queries[i%len(queries)]
+			minDistance := maxRange + 1
+			for _, rabbit := range rabbits.Animals {
+				if distance := abs(pos.X-rabbit.Position.X) + abs(pos.Y-rabbit.Position.Y); distance < minDistance {
+					minDistance = distance
+				}
+			}
+		}
+	})
+}
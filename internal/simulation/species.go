@@ -0,0 +1,115 @@
+package simulation
+
+import "foxes-rabbits-simulation/internal/config"
+
+// Species is a kind of animal: its configured parameters, and the species it
+// hunts and is hunted by
+type Species struct {
+	config.Species
+
+	prey      []*Species
+	predators []*Species
+	grazes    bool
+	index     int // Of its population in World.Populations
+}
+
+// Predators returns the species hunting this one
+func (s *Species) Predators() []*Species {
+	return s.predators
+}
+
+// Grazes reports whether the species eats grass
+func (s *Species) Grazes() bool {
+	return s.grazes
+}
+
+// traits returns the traits of animals of the species placed in the world, before any evolution
+func (s *Species) traits() Traits {
+	return Traits{
+		Speed:                 1,
+		Vision:                s.Vision,
+		Metabolism:            1,
+		ReproductionThreshold: s.ReproductionCost,
+	}
+}
+
+// Population holds the animals of one species
+type Population struct {
+	Species *Species
+	Animals []*Animal
+
+	// LastTick counts what happened to the population during the most recent Update
+	LastTick TickStats
+
+	// Scent is the scent the population left on each cell, indexed [x][y].
+	// It's nil until the first Update with a positive ScentDeposit
+	Scent       [][]float64
+	scentBuffer [][]float64
+
+	index *SpatialIndex
+}
+
+// newPopulations creates an empty population for every species in the config,
+// in the order of Config.AllSpecies, and links each species to its prey and predators
+func newPopulations(cfg *config.Config) []*Population {
+	all := cfg.AllSpecies()
+	populations := make([]*Population, len(all))
+	byName := make(map[string]*Species, len(all))
+	for i := range all {
+		species := &Species{Species: all[i], grazes: all[i].Eats(config.Grass), index: i}
+		byName[species.Name] = species
+		populations[i] = &Population{
+			Species: species,
+			Animals: make([]*Animal, 0),
+			index:   NewSpatialIndex(cfg.WorldWidth, cfg.WorldHeight, cfg.Torus),
+		}
+	}
+
+	for _, population := range populations {
+		hunter := population.Species
+		for _, name := range hunter.Prey {
+			if prey := byName[name]; prey != nil {
+				hunter.prey = append(hunter.prey, prey)
+				prey.predators = append(prey.predators, hunter)
+			}
+		}
+	}
+	return populations
+}
+
+// Population returns the population of the species with the given name, or nil if there's none
+func (w *World) Population(name string) *Population {
+	for _, population := range w.Populations {
+		if population.Species.Name == name {
+			return population
+		}
+	}
+	return nil
+}
+
+// populationOf returns the population an animal of the given species belongs to
+func (w *World) populationOf(species *Species) *Population {
+	return w.Populations[species.index]
+}
+
+// nearest returns the animal of any of the given species closest to pos within maxRange
+func (w *World) nearest(species []*Species, pos Position, maxRange int) (*Animal, bool) {
+	var nearest *Animal
+	found := false
+	minDistance := 0
+
+	for _, s := range species {
+		candidate, ok := w.populationOf(s).index.Nearest(pos, maxRange)
+		if !ok {
+			continue
+		}
+
+		// Ties go to the lower position, like within a single species
+		dx, dy := w.populationOf(s).index.distance(pos, candidate.Position)
+		distance := dx + dy
+		if !found || distance < minDistance || (distance == minDistance && candidate.Position.Less(nearest.Position)) {
+			nearest, minDistance, found = candidate, distance, true
+		}
+	}
+	return nearest, found
+}
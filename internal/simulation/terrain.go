@@ -0,0 +1,80 @@
+package simulation
+
+import "fmt"
+
+// Terrain is the kind of ground a cell is made of
+type Terrain uint8
+
+const (
+	Meadow Terrain = iota // The default, grows grass as configured
+	Forest                // Passable, grows grass with the forest parameters
+	Water                 // Blocks movement, grows nothing
+	Rock                  // Blocks movement, grows nothing
+)
+
+var terrainNames = [...]string{
+	Meadow: "meadow",
+	Forest: "forest",
+	Water:  "water",
+	Rock:   "rock",
+}
+
+func (t Terrain) String() string {
+	if int(t) < len(terrainNames) {
+		return terrainNames[t]
+	}
+	return fmt.Sprintf("Terrain(%d)", int(t))
+}
+
+// ParseTerrain returns the terrain with the given name, as printed by String
+func ParseTerrain(name string) (Terrain, error) {
+	for t, n := range terrainNames {
+		if n == name {
+			return Terrain(t), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown terrain %q", name)
+}
+
+// MarshalText writes terrain by name, so snapshots stay readable
+func (t Terrain) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Terrain) UnmarshalText(text []byte) error {
+	parsed, err := ParseTerrain(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Passable reports whether animals can stand on the terrain
+func (t Terrain) Passable() bool {
+	return t == Meadow || t == Forest
+}
+
+// SetTerrain changes the terrain of a cell and adapts its grass to it.
+// Grass above the new maximum is cut down to it
+func (w *World) SetTerrain(x, y int, terrain Terrain) {
+	w.TerrainGrid[x][y] = terrain
+
+	grass := w.GrassGrid[x][y]
+	switch terrain {
+	case Meadow:
+		grass.GrowthRate, grass.MaxAmount = w.Config.GrassGrowthRate, w.Config.GrassMaxAmount
+	case Forest:
+		grass.GrowthRate, grass.MaxAmount = w.Config.ForestGrassGrowthRate, w.Config.ForestGrassMaxAmount
+	default:
+		grass.GrowthRate, grass.MaxAmount = 0, 0
+	}
+	grass.Amount = min(grass.Amount, grass.MaxAmount)
+}
+
+// IsPositionBlocked checks if animals can't step onto a position, because it's
+// outside the world, occupied by an animal or impassable terrain
+func (w *World) IsPositionBlocked(x, y int) bool {
+	x, y, ok := w.Wrap(x, y)
+	return !ok || w.occupancy[x][y] != nil || !w.TerrainGrid[x][y].Passable()
+}
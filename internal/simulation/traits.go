@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Traits are the heritable properties of an individual. Newborns inherit their
+// parent's traits, each changed by a random mutation scaled by MutationRate
+type Traits struct {
+	Speed                 float64 // Steps per tick, the fraction is the chance of an extra step
+	Vision                int     // How far the animal sees prey or predators
+	Metabolism            float64 // Scales the energy gained from food and spent moving
+	ReproductionThreshold int     // Energy needed to reproduce, never below the reproduction cost
+}
+
+// Limits keeping mutated traits in a range the simulation can run with
+const (
+	minSpeed      = 0.1
+	maxSpeed      = 4
+	maxVision     = 100
+	minMetabolism = 0.1
+	maxMetabolism = 4
+)
+
+// inherit returns the traits of a newborn: a copy of t where each trait is changed by
+// a normally distributed amount with a standard deviation of rate times its value
+func (t Traits) inherit(rng *rand.Rand, rate float64, reproductionCost int) Traits {
+	if rate == 0 {
+		return t
+	}
+
+	mutate := func(value float64) float64 {
+		return value + rng.NormFloat64()*rate*value
+	}
+	mutateInt := func(value int) int {
+		return value + int(math.Round(rng.NormFloat64()*rate*float64(max(value, 1))))
+	}
+
+	return Traits{
+		Speed:                 min(max(mutate(t.Speed), minSpeed), maxSpeed),
+		Vision:                min(max(mutateInt(t.Vision), 0), maxVision),
+		Metabolism:            min(max(mutate(t.Metabolism), minMetabolism), maxMetabolism),
+		ReproductionThreshold: max(mutateInt(t.ReproductionThreshold), reproductionCost),
+	}
+}
+
+// mix returns the traits of a young of two parents, taking each trait from either one at random
+func (t Traits) mix(other Traits, rng *rand.Rand) Traits {
+	if rng.IntN(2) == 0 {
+		t.Speed = other.Speed
+	}
+	if rng.IntN(2) == 0 {
+		t.Vision = other.Vision
+	}
+	if rng.IntN(2) == 0 {
+		t.Metabolism = other.Metabolism
+	}
+	if rng.IntN(2) == 0 {
+		t.ReproductionThreshold = other.ReproductionThreshold
+	}
+	return t
+}
+
+// takeSteps makes as many moves as the animal's speed allows this tick, starting
+// with the planned one. Further moves are planned with replan as they're taken
+func (a *Animal) takeSteps(first movePlan, world *World, replan func() movePlan) {
+	steps := int(a.Traits.Speed)
+	if fraction := a.Traits.Speed - float64(steps); fraction > 0 && world.rng.Float64() < fraction {
+		steps++
+	}
+
+	plan := first
+	for step := 0; step < steps; step++ {
+		if step > 0 {
+			plan = replan()
+		}
+		a.applyMove(plan, world)
+	}
+}
+
+// moveCost returns the energy spent moving in a tick, which grows with speed and metabolism
+func (a *Animal) moveCost(costPerMove int) int {
+	return int(math.Round(float64(costPerMove) * a.Traits.Speed * a.Traits.Metabolism))
+}
+
+// energyFrom returns the energy the animal gets out of food worth gain
+func (a *Animal) energyFrom(gain int) int {
+	return int(math.Round(float64(gain) * a.Traits.Metabolism))
+}
@@ -2,31 +2,69 @@ package simulation
 
 import (
 	"foxes-rabbits-simulation/internal/config"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
 )
 
+// TickStats counts the births and deaths of a population during a single tick
+type TickStats struct {
+	Births    int
+	Starved   int
+	Eaten     int
+	DiedOfAge int
+}
+
 type World struct {
-	Width     int
-	Height    int
-	Foxes     []*Fox
-	Rabbits   []*Rabbit
+	Width  int
+	Height int
+	// Populations holds the animals of every species, in the order they're updated
+	Populations []*Population
+	// Burrows are the refuges dug into the world, add them with AddBurrow
+	Burrows   []*Burrow
 	GrassGrid [][]*Grass
-	Config    *config.Config
+	// TerrainGrid holds the terrain of each cell, change it with SetTerrain
+	TerrainGrid [][]Terrain
+	Config      *config.Config
+	Seed        uint64
+	Tick        int
+
+	// occupancy holds the animal standing on each cell, indexed [x][y] like GrassGrid
+	occupancy [][]*Animal
+
+	// torus makes the world wrap around its edges instead of being walled in
+	torus bool
+
+	listeners []func(Event)
+	nextID    int
+
+	// rng is the only source of randomness in the simulation, so a world
+	// created with the same seed and config always evolves the same way.
+	// src is kept alongside it so its state can be saved in snapshots
+	rng *rand.Rand
+	src *rand.PCG
 }
 
-func NewWorld(cfg *config.Config) *World {
+func NewWorld(cfg *config.Config, seed uint64) *World {
+	src := rand.NewPCG(seed, seed)
 	world := &World{
-		Width:   cfg.WorldWidth,
-		Height:  cfg.WorldHeight,
-		Foxes:   make([]*Fox, 0),
-		Rabbits: make([]*Rabbit, 0),
-		Config:  cfg,
+		Width:       cfg.WorldWidth,
+		Height:      cfg.WorldHeight,
+		Populations: newPopulations(cfg),
+		Config:      cfg,
+		Seed:        seed,
+		torus:       cfg.Torus,
+		rng:         rand.New(src),
+		src:         src,
 	}
 
-	// Initialize grass grid
+	// Initialize grass grid, meadow terrain and empty occupancy grid
 	world.GrassGrid = make([][]*Grass, world.Width)
+	world.TerrainGrid = make([][]Terrain, world.Width)
+	world.occupancy = make([][]*Animal, world.Width)
 	for x := 0; x < world.Width; x++ {
 		world.GrassGrid[x] = make([]*Grass, world.Height)
+		world.TerrainGrid[x] = make([]Terrain, world.Height)
+		world.occupancy[x] = make([]*Animal, world.Height)
 		for y := 0; y < world.Height; y++ {
 			world.GrassGrid[x][y] = NewGrass(cfg)
 		}
@@ -36,107 +74,225 @@ func NewWorld(cfg *config.Config) *World {
 }
 
 func (w *World) Update() {
-	// Update and collect new animals
-	var newFoxes []*Fox
-	var newRabbits []*Rabbit
-
-	// Process foxes
-	for i := 0; i < len(w.Foxes); i++ {
-		if !w.Foxes[i].IsDead() {
-			w.Foxes[i].Move(w)
-			w.Foxes[i].Eat(w)
-
-			if newFox := w.Foxes[i].Reproduce(w); newFox != nil {
-				newFoxes = append(newFoxes, newFox)
-			}
-		}
+	w.Tick++
+	for _, population := range w.Populations {
+		population.LastTick = TickStats{}
+	}
+
+	// Animals grow older before acting, and those too old die
+	for _, population := range w.Populations {
+		w.ageAnimals(population)
 	}
 
-	// Process rabbits
-	for i := 0; i < len(w.Rabbits); i++ {
-		if !w.Rabbits[i].IsDead() {
-			w.Rabbits[i].Move(w)
-			x, y := w.Rabbits[i].Position.X, w.Rabbits[i].Position.Y
-			w.Rabbits[i].Eat(w.GrassGrid[x][y])
+	// Update and collect new animals, one species after another
+	var newborns [][]*Animal
 
-			if newRabbit := w.Rabbits[i].Reproduce(w); newRabbit != nil {
-				newRabbits = append(newRabbits, newRabbit)
+	if w.Config.ParallelWorkers > 0 {
+		newborns = w.updateAnimalsParallel()
+	} else {
+		newborns = make([][]*Animal, len(w.Populations))
+		for i, population := range w.Populations {
+			for j := 0; j < len(population.Animals); j++ {
+				animal := population.Animals[j]
+				switch {
+				case animal.IsDead():
+				case animal.Burrow != nil:
+					newborns[i] = append(newborns[i], w.stayHidden(animal)...)
+				default:
+					animal.Move(w)
+					if w.hide(animal) {
+						newborns[i] = append(newborns[i], w.waitInBurrow(animal)...)
+					} else {
+						newborns[i] = append(newborns[i], w.feedAndBreed(animal)...)
+					}
+				}
 			}
 		}
 	}
 
 	// Add new animals
-	w.Foxes = append(w.Foxes, newFoxes...)
-	w.Rabbits = append(w.Rabbits, newRabbits...)
+	for i, population := range w.Populations {
+		population.Animals = append(population.Animals, newborns[i]...)
+	}
 
 	// Remove dead animals using filter pattern
-	w.Foxes = filterAlive(w.Foxes)
-	w.Rabbits = filterAlive(w.Rabbits)
+	for _, population := range w.Populations {
+		w.filterAlive(population)
+	}
 
 	// Grow grass
-	for x := 0; x < w.Width; x++ {
+	if w.Config.ParallelWorkers > 0 {
+		w.growGrassParallel()
+	} else {
+		w.growGrass(0, w.Width, func(pos Position) {
+			w.emit(Event{Kind: GrassRegrew, Position: pos})
+		})
+	}
+
+	// Leave, spread and fade scent
+	if w.Config.ScentDeposit > 0 {
+		w.updateScent()
+	}
+}
+
+// feedAndBreed lets an animal that has moved eat and reproduce.
+// Newborns take their cell right away but only act from the next tick
+func (w *World) feedAndBreed(animal *Animal) []*Animal {
+	animal.Eat(w)
+	return w.born(animal, animal.Reproduce(w))
+}
+
+// born counts and reports the young a mother just gave birth to, and returns them
+func (w *World) born(mother *Animal, litter []*Animal) []*Animal {
+	for _, young := range litter {
+		w.populationOf(young.Species).LastTick.Births++
+		w.emit(Event{Kind: Born, Animal: young, Other: mother, Position: young.Position})
+	}
+	return litter
+}
+
+// growGrass grows the grass in columns fromX up to toX, reporting each cell that regrew
+func (w *World) growGrass(fromX, toX int, regrew func(Position)) {
+	for x := fromX; x < toX; x++ {
 		for y := 0; y < w.Height; y++ {
-			w.GrassGrid[x][y].Grow()
+			if w.GrassGrid[x][y].Grow() {
+				regrew(Position{X: x, Y: y})
+			}
 		}
 	}
 }
 
-// Helper functions to remove dead animals
-func filterAlive[T Animal](animals []T) []T {
-	alive := animals[:0]
-	for _, animal := range animals {
+// filterAlive removes the dead animals of a population, freeing their cells or burrows and counting those that starved
+func (w *World) filterAlive(population *Population) {
+	alive := population.Animals[:0]
+	for _, animal := range population.Animals {
 		if !animal.IsDead() {
 			alive = append(alive, animal)
+			continue
 		}
-	}
-	return alive
-}
 
-// Initialize populates the world with animals
-func (w *World) Initialize(numFoxes, numRabbits int) {
-	for i := 0; i < numFoxes; i++ {
-		x, y := w.getRandomEmptyPosition()
-		w.Foxes = append(w.Foxes, NewFox(x, y, w.Config))
+		if animal.cause == starvation {
+			population.LastTick.Starved++
+			w.emit(Event{Kind: Starved, Animal: animal, Position: animal.Position})
+		}
+		w.vacate(animal)
+		w.leaveBurrow(animal)
+		population.index.Remove(animal)
 	}
+	population.Animals = alive
+}
 
-	for i := 0; i < numRabbits; i++ {
-		x, y := w.getRandomEmptyPosition()
-		w.Rabbits = append(w.Rabbits, NewRabbit(x, y, w.Config))
+// Initialize digs the initial burrows and populates the world with the initial animals of every species
+func (w *World) Initialize() {
+	w.digBurrows()
+	for _, population := range w.Populations {
+		for i := 0; i < population.Species.InitialCount; i++ {
+			x, y := w.getRandomEmptyPosition()
+			w.AddAnimal(NewAnimal(population.Species, x, y, w.Config))
+		}
 	}
 }
 
-// getRandomEmptyPosition finds an unoccupied position
+// getRandomEmptyPosition finds an unoccupied, passable position
 func (w *World) getRandomEmptyPosition() (int, int) {
 	for {
-		x := rand.Intn(w.Width)
-		y := rand.Intn(w.Height)
+		x := w.rng.IntN(w.Width)
+		y := w.rng.IntN(w.Height)
 
-		if !w.IsPositionOccupied(x, y) {
+		if !w.IsPositionBlocked(x, y) {
 			return x, y
 		}
 	}
 }
 
-// IsPositionOccupied checks if a position is occupied by any animal
+// IsPositionOccupied checks if a position is occupied by any animal.
+// On a torus, positions outside the world wrap around onto it
 func (w *World) IsPositionOccupied(x, y int) bool {
 	// Check if position is outside world boundaries
-	if x < 0 || x >= w.Width || y < 0 || y >= w.Height {
+	x, y, ok := w.Wrap(x, y)
+	if !ok {
 		return true
 	}
 
-	// Check if any fox is at this position
-	for _, fox := range w.Foxes {
-		if fox.Position.X == x && fox.Position.Y == y {
-			return true
-		}
+	return w.occupancy[x][y] != nil
+}
+
+// AnimalAt returns the animal standing on a cell, or nil if the cell is empty or outside the world
+func (w *World) AnimalAt(x, y int) *Animal {
+	x, y, ok := w.Wrap(x, y)
+	if !ok {
+		return nil
 	}
+	return w.occupancy[x][y]
+}
 
-	// Check if any rabbit is at this position
-	for _, rabbit := range w.Rabbits {
-		if rabbit.Position.X == x && rabbit.Position.Y == y {
-			return true
-		}
+// AddAnimal puts an animal into the population of its species. Its cell must be empty
+func (w *World) AddAnimal(animal *Animal) {
+	population := w.populationOf(animal.Species)
+	population.Animals = append(population.Animals, animal)
+	w.place(animal)
+}
+
+// place registers an animal in the occupancy grid and spatial index without adding it to its population
+func (w *World) place(animal *Animal) {
+	species := animal.Species
+	w.assignID(animal)
+	w.assignLifespan(animal, species.Lifespan, species.LifespanSpread)
+	w.assignSex(animal)
+	w.occupancy[animal.Position.X][animal.Position.Y] = animal
+	w.populationOf(species).index.Insert(animal)
+}
+
+// assignID gives a newly placed animal the next free ID, unless it already has one
+func (w *World) assignID(a *Animal) {
+	if a.ID == 0 {
+		w.nextID++
+		a.ID = w.nextID
 	}
+}
+
+// kill removes an eaten animal from the map at once.
+// It stays in its population as a dead animal until the end of the tick
+func (w *World) kill(prey, predator *Animal) {
+	prey.Energy = 0
+	prey.cause = predation
+	population := w.populationOf(prey.Species)
+	population.LastTick.Eaten++
+	w.emit(Event{Kind: Eaten, Animal: prey, Other: predator, Position: prey.Position})
+	w.vacate(prey)
+	population.index.Remove(prey)
+}
+
+// RemoveAnimalAt takes the animal on a cell out of the world right away,
+// without counting it as a death. It reports whether there was one
+func (w *World) RemoveAnimalAt(x, y int) bool {
+	animal := w.AnimalAt(x, y)
+	if animal == nil {
+		return false
+	}
+
+	population := w.populationOf(animal.Species)
+	population.Animals = slices.DeleteFunc(population.Animals, func(a *Animal) bool { return a == animal })
+	population.index.Remove(animal)
+	w.vacate(animal)
+	return true
+}
+
+// vacate frees the cell of an animal, unless another animal already took it
+func (w *World) vacate(animal *Animal) {
+	pos := animal.Position
+	if w.occupancy[pos.X][pos.Y] == animal {
+		w.occupancy[pos.X][pos.Y] = nil
+	}
+}
+
+// relocate moves whatever animal stands on from to the empty cell to
+func (w *World) relocate(from, to Position) {
+	animal := w.occupancy[from.X][from.Y]
+	w.occupancy[from.X][from.Y] = nil
+	w.occupancy[to.X][to.Y] = animal
+
+	w.populationOf(animal.Species).index.Move(animal, from, to)
 
-	return false
+	w.emit(Event{Kind: Moved, Animal: animal, Position: to, From: from})
 }
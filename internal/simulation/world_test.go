@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"bytes"
+	"foxes-rabbits-simulation/internal/config"
+	"slices"
+	"testing"
+)
+
+// largeWorldConfig is a 1000x1000 world with 2000 foxes and 20000 rabbits,
+// where scanning every animal for each query was the bottleneck
+func largeWorldConfig() *config.Config {
+	cfg := config.NewConfig()
+	cfg.WorldWidth, cfg.WorldHeight = 1000, 1000
+	cfg.InitialFoxes, cfg.InitialRabbits = 2000, 20000
+	return cfg
+}
+
+func BenchmarkWorldUpdate(b *testing.B) {
+	world := NewWorld(largeWorldConfig(), 1)
+	world.Initialize()
+
+	b.ResetTimer()
+	for range b.N {
+		world.Update()
+	}
+}
+
+// testConfigs are worlds exercising the default rules and every optional one
+func testConfigs() map[string]*config.Config {
+	defaults := config.NewConfig()
+
+	features := *defaults
+	features.PathfindingBudget = 20
+	features.MutationRate = 0.05
+	features.SexualReproduction = true
+	features.RabbitLitterSize = 2.5
+	features.LitterSizeDistribution = "poisson"
+	features.FoxGestation, features.RabbitGestation = 5, 3
+	features.FoxLifespan, features.FoxLifespanSpread = 300, 30
+	features.RabbitLifespan, features.RabbitLifespanSpread = 120, 10
+	features.ScentDeposit, features.Senses = 1, "both"
+	features.BurrowCount = 10
+
+	return map[string]*config.Config{"defaults": defaults, "features": &features}
+}
+
+// run advances a new world with the given config and seed by ticks, and returns
+// its population counts after every tick and a snapshot of its final state
+func run(t *testing.T, cfg *config.Config, seed uint64, ticks int) ([]int, []byte) {
+	t.Helper()
+	cfg = cloneConfig(cfg)
+	world := NewWorld(cfg, seed)
+	world.Initialize()
+	return advance(t, world, ticks)
+}
+
+// advance updates world ticks times, returning the same as run
+func advance(t *testing.T, world *World, ticks int) ([]int, []byte) {
+	t.Helper()
+	var counts []int
+	for range ticks {
+		world.Update()
+		for _, population := range world.Populations {
+			counts = append(counts, len(population.Animals))
+		}
+	}
+	return counts, save(t, world)
+}
+
+// save returns a snapshot of world, leaving out the number of parallel workers
+func save(t *testing.T, world *World) []byte {
+	t.Helper()
+	cfg := *world.Config
+	cfg.ParallelWorkers = 0
+	world.Config = &cfg
+	var out bytes.Buffer
+	if err := world.Save(&out); err != nil {
+		t.Fatalf("saving world: %s", err)
+	}
+	return out.Bytes()
+}
+
+func cloneConfig(cfg *config.Config) *config.Config {
+	clone := *cfg
+	return &clone
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+	for name, cfg := range testConfigs() {
+		t.Run(name, func(t *testing.T) {
+			counts, state := run(t, cfg, 7, 200)
+			againCounts, againState := run(t, cfg, 7, 200)
+			if !slices.Equal(counts, againCounts) || !bytes.Equal(state, againState) {
+				t.Fatal("two runs with the same seed and config differ")
+			}
+
+			otherCounts, _ := run(t, cfg, 8, 200)
+			if slices.Equal(counts, otherCounts) {
+				t.Error("runs with different seeds are identical")
+			}
+		})
+	}
+}
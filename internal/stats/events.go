@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"foxes-rabbits-simulation/internal/simulation"
+	"os"
+)
+
+// eventRecord is the NDJSON form of a simulation.Event
+type eventRecord struct {
+	Tick    int    `json:"tick"`
+	Kind    string `json:"kind"`
+	Species string `json:"species,omitempty"`
+	ID      int    `json:"id,omitempty"`
+	OtherID int    `json:"other_id,omitempty"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	FromX   *int   `json:"from_x,omitempty"`
+	FromY   *int   `json:"from_y,omitempty"`
+}
+
+// EventLog writes simulation events to a file as NDJSON, one event per line.
+// Write errors are kept and reported by Close
+type EventLog struct {
+	out   *bufio.Writer
+	file  *os.File
+	kinds map[simulation.EventKind]bool
+	err   error
+}
+
+// CreateEventLog opens path for writing. Only events of the given kinds are
+// logged, or every event if no kinds are given
+func CreateEventLog(path string, kinds ...simulation.EventKind) (*EventLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &EventLog{out: bufio.NewWriter(file), file: file}
+	if len(kinds) > 0 {
+		l.kinds = make(map[simulation.EventKind]bool)
+		for _, kind := range kinds {
+			l.kinds[kind] = true
+		}
+	}
+	return l, nil
+}
+
+// Log writes one event, it can be passed directly to World.Subscribe
+func (l *EventLog) Log(event simulation.Event) {
+	if l.err != nil || (l.kinds != nil && !l.kinds[event.Kind]) {
+		return
+	}
+
+	record := eventRecord{
+		Tick: event.Tick,
+		Kind: event.Kind.String(),
+		X:    event.Position.X,
+		Y:    event.Position.Y,
+	}
+	if event.Animal != nil {
+		record.Species = event.Animal.Species.Name
+		record.ID = event.Animal.ID
+	}
+	if event.Other != nil {
+		record.OtherID = event.Other.ID
+	}
+	if event.Kind == simulation.Moved || event.Kind == simulation.Hid || event.Kind == simulation.Emerged {
+		record.FromX, record.FromY = &event.From.X, &event.From.Y
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		l.err = err
+		return
+	}
+	_, l.err = l.out.Write(append(data, '\n'))
+}
+
+// Close flushes buffered events and closes the file
+func (l *EventLog) Close() error {
+	if err := l.out.Flush(); l.err == nil {
+		l.err = err
+	}
+	if err := l.file.Close(); l.err == nil {
+		l.err = err
+	}
+	return l.err
+}
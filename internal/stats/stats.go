@@ -0,0 +1,321 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"foxes-rabbits-simulation/internal/simulation"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Row holds the population statistics of one tick
+type Row struct {
+	Tick    int
+	Grass   int
+	Species []SpeciesRow // In the world's species order
+}
+
+// SpeciesRow holds the statistics of one species
+type SpeciesRow struct {
+	Name       string
+	Plural     string
+	Count      int
+	MeanEnergy float64
+	Births     int
+	Starved    int
+	Eaten      int
+	DiedOfAge  int
+	Traits     Traits
+	Hunted     bool // Only species with predators have an eaten column
+}
+
+// Traits summarizes the distribution of each heritable trait in a population
+// by its mean and standard deviation
+type Traits struct {
+	SpeedMean      float64 `json:"speed_mean"`
+	SpeedSD        float64 `json:"speed_sd"`
+	VisionMean     float64 `json:"vision_mean"`
+	VisionSD       float64 `json:"vision_sd"`
+	MetabolismMean float64 `json:"metabolism_mean"`
+	MetabolismSD   float64 `json:"metabolism_sd"`
+	ThresholdMean  float64 `json:"reproduction_threshold_mean"`
+	ThresholdSD    float64 `json:"reproduction_threshold_sd"`
+}
+
+// traitColumns names the CSV columns of Traits, in the same order as Traits.values
+var traitColumns = []string{
+	"speed_mean", "speed_sd", "vision_mean", "vision_sd",
+	"metabolism_mean", "metabolism_sd", "reproduction_threshold_mean", "reproduction_threshold_sd",
+}
+
+func (t Traits) values() []string {
+	values := []float64{
+		t.SpeedMean, t.SpeedSD, t.VisionMean, t.VisionSD,
+		t.MetabolismMean, t.MetabolismSD, t.ThresholdMean, t.ThresholdSD,
+	}
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = strconv.FormatFloat(value, 'f', 3, 64)
+	}
+	return formatted
+}
+
+// field is a named value of a Row: an int, a float64 or the Traits of a species
+type field struct {
+	name  string
+	value any
+}
+
+// fields lists the values of a row in column order: the populations, grass, mean
+// energies, births, deaths and traits, each column repeated for every species
+func (r Row) fields() []field {
+	fields := []field{{"tick", r.Tick}}
+	for _, s := range r.Species {
+		fields = append(fields, field{s.Plural, s.Count})
+	}
+	fields = append(fields, field{"grass", r.Grass})
+	for _, s := range r.Species {
+		fields = append(fields, field{"mean_" + s.Name + "_energy", s.MeanEnergy})
+	}
+	for _, s := range r.Species {
+		fields = append(fields, field{s.Name + "_births", s.Births})
+	}
+	for _, s := range r.Species {
+		fields = append(fields, field{s.Plural + "_starved", s.Starved})
+	}
+	for _, s := range r.Species {
+		if s.Hunted {
+			fields = append(fields, field{s.Plural + "_eaten", s.Eaten})
+		}
+	}
+	for _, s := range r.Species {
+		fields = append(fields, field{s.Plural + "_died_of_age", s.DiedOfAge})
+	}
+	for _, s := range r.Species {
+		fields = append(fields, field{s.Name + "_traits", s.Traits})
+	}
+	return fields
+}
+
+// header lists the CSV columns, in the same order as Row.values. Traits are
+// spread over one column per summary, prefixed with the species name
+func (r Row) header() []string {
+	var names []string
+	for _, f := range r.fields() {
+		if _, ok := f.value.(Traits); ok {
+			names = append(names, prefixed(strings.TrimSuffix(f.name, "traits"), traitColumns)...)
+		} else {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+func prefixed(prefix string, columns []string) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = prefix + column
+	}
+	return names
+}
+
+func (r Row) values() []string {
+	var values []string
+	for _, f := range r.fields() {
+		switch value := f.value.(type) {
+		case int:
+			values = append(values, strconv.Itoa(value))
+		case float64:
+			values = append(values, strconv.FormatFloat(value, 'f', 3, 64))
+		case Traits:
+			values = append(values, value.values()...)
+		}
+	}
+	return values
+}
+
+// MarshalJSON writes the row as an object with the CSV column names as keys, in
+// the same order, except that the traits of each species are kept in one object
+func (r Row) MarshalJSON() ([]byte, error) {
+	data := []byte{'{'}
+	for i, f := range r.fields() {
+		if i > 0 {
+			data = append(data, ',')
+		}
+		name, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		data = append(append(append(data, name...), ':'), value...)
+	}
+	return append(data, '}'), nil
+}
+
+// Collect computes the statistics of the world's current state and most recent tick
+func Collect(world *simulation.World) Row {
+	row := Row{Tick: world.Tick}
+
+	for x := 0; x < world.Width; x++ {
+		for y := 0; y < world.Height; y++ {
+			row.Grass += world.GrassGrid[x][y].Amount
+		}
+	}
+
+	for _, population := range world.Populations {
+		species := population.Species
+		s := SpeciesRow{
+			Name:      species.Name,
+			Plural:    species.PluralName(),
+			Count:     len(population.Animals),
+			Births:    population.LastTick.Births,
+			Starved:   population.LastTick.Starved,
+			Eaten:     population.LastTick.Eaten,
+			DiedOfAge: population.LastTick.DiedOfAge,
+			Hunted:    len(species.Predators()) > 0,
+		}
+
+		if len(population.Animals) > 0 {
+			total := 0
+			traits := make([]simulation.Traits, len(population.Animals))
+			for i, animal := range population.Animals {
+				total += animal.Energy
+				traits[i] = animal.Traits
+			}
+			s.MeanEnergy = float64(total) / float64(len(population.Animals))
+			s.Traits = summarizeTraits(traits)
+		}
+		row.Species = append(row.Species, s)
+	}
+
+	return row
+}
+
+// summarizeTraits computes the mean and standard deviation of every trait of a non-empty population
+func summarizeTraits(traits []simulation.Traits) Traits {
+	meanAndSD := func(value func(simulation.Traits) float64) (float64, float64) {
+		mean := 0.0
+		for _, t := range traits {
+			mean += value(t)
+		}
+		mean /= float64(len(traits))
+
+		variance := 0.0
+		for _, t := range traits {
+			variance += (value(t) - mean) * (value(t) - mean)
+		}
+		return mean, math.Sqrt(variance / float64(len(traits)))
+	}
+
+	var summary Traits
+	summary.SpeedMean, summary.SpeedSD = meanAndSD(func(t simulation.Traits) float64 { return t.Speed })
+	summary.VisionMean, summary.VisionSD = meanAndSD(func(t simulation.Traits) float64 { return float64(t.Vision) })
+	summary.MetabolismMean, summary.MetabolismSD = meanAndSD(func(t simulation.Traits) float64 { return t.Metabolism })
+	summary.ThresholdMean, summary.ThresholdSD = meanAndSD(func(t simulation.Traits) float64 { return float64(t.ReproductionThreshold) })
+	return summary
+}
+
+// Format selects how a Recorder writes rows
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// FormatFromPath picks a format from a file extension, defaulting to CSV
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		return NDJSON
+	}
+	return CSV
+}
+
+// Recorder writes one row of statistics per tick to a file.
+// Write errors are kept and reported by Close
+type Recorder struct {
+	out    *bufio.Writer
+	file   io.Closer
+	format Format
+	csv    *csv.Writer
+	err    error
+
+	wroteHeader bool
+}
+
+// Create opens path for writing and returns a Recorder for it.
+// An empty format is inferred from the file extension
+func Create(path string, format Format) (*Recorder, error) {
+	if format == "" {
+		format = FormatFromPath(path)
+	}
+	if format != CSV && format != NDJSON {
+		return nil, fmt.Errorf("unknown stats format %q, expected %q or %q", format, CSV, NDJSON)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		out:    bufio.NewWriter(file),
+		file:   file,
+		format: format,
+	}
+	if format == CSV {
+		r.csv = csv.NewWriter(r.out)
+	}
+	return r, nil
+}
+
+// Record appends the statistics of the world's current tick
+func (r *Recorder) Record(world *simulation.World) {
+	if r.err != nil {
+		return
+	}
+
+	row := Collect(world)
+	if r.format == CSV {
+		// The columns depend on the world's species, so they're written with the first row
+		if !r.wroteHeader {
+			r.wroteHeader = true
+			if r.err = r.csv.Write(row.header()); r.err != nil {
+				return
+			}
+		}
+		r.err = r.csv.Write(row.values())
+		return
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		r.err = err
+		return
+	}
+	_, r.err = r.out.Write(append(data, '\n'))
+}
+
+// Close flushes buffered rows and closes the file
+func (r *Recorder) Close() error {
+	if r.csv != nil {
+		r.csv.Flush()
+		if r.err == nil {
+			r.err = r.csv.Error()
+		}
+	}
+	if err := r.out.Flush(); r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
@@ -0,0 +1,313 @@
+package sweep
+
+import (
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Param is a config field and the values a sweep tries for it
+type Param struct {
+	Name   string
+	Values []string
+}
+
+// ParseParam reads a parameter range written as Name=start:stop:step (stop included)
+// or as a list of values, Name=v1,v2,v3
+func ParseParam(spec string) (Param, error) {
+	name, values, ok := strings.Cut(spec, "=")
+	if !ok || name == "" || values == "" {
+		return Param{}, fmt.Errorf("expected Name=start:stop:step or Name=v1,v2,..., got %q", spec)
+	}
+	param := Param{Name: strings.TrimSpace(name)}
+
+	if !strings.Contains(values, ":") {
+		for _, value := range strings.Split(values, ",") {
+			param.Values = append(param.Values, strings.TrimSpace(value))
+		}
+		return param, nil
+	}
+
+	bounds := strings.Split(values, ":")
+	if len(bounds) != 3 {
+		return Param{}, fmt.Errorf("%s: expected start:stop:step, got %q", param.Name, values)
+	}
+	var start, stop, step float64
+	decimals := 0
+	for i, target := range []*float64{&start, &stop, &step} {
+		n, err := strconv.ParseFloat(strings.TrimSpace(bounds[i]), 64)
+		if err != nil {
+			return Param{}, fmt.Errorf("%s: %q is not a number", param.Name, bounds[i])
+		}
+		*target = n
+		if i != 1 {
+			decimals = max(decimals, decimalPlaces(n))
+		}
+	}
+	if step <= 0 || stop < start {
+		return Param{}, fmt.Errorf("%s: range %q must have a positive step and stop >= start", param.Name, values)
+	}
+
+	// Count steps up front so float rounding can't drop or add the last value
+	count := int((stop-start)/step+1e-9) + 1
+	for i := 0; i < count; i++ {
+		// Values are written with as many decimals as start and step, so 0.1:0.3:0.1 gives 0.3, not 0.30000000000000004
+		value := strconv.FormatFloat(start+float64(i)*step, 'f', decimals, 64)
+		if strings.Contains(value, ".") {
+			value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+		}
+		param.Values = append(param.Values, value)
+	}
+	return param, nil
+}
+
+// decimalPlaces counts the digits after the decimal point of the shortest plain notation of n
+func decimalPlaces(n float64) int {
+	_, fraction, found := strings.Cut(strconv.FormatFloat(n, 'f', -1, 64), ".")
+	if !found {
+		return 0
+	}
+	return len(fraction)
+}
+
+// Options controls how every simulation of a sweep is run
+type Options struct {
+	Ticks int
+	Runs  int    // simulations per combination, each with its own seed
+	Seed  uint64 // run i of every combination uses Seed+i
+	Jobs  int    // simulations run concurrently
+}
+
+// Result summarizes the runs of one parameter combination.
+// Extinction ticks and the period are averaged over the runs where they occurred
+type Result struct {
+	Values       []string
+	Runs         int
+	Coexisted    int             // runs where every species survived to the end
+	Species      []SpeciesResult // in the world's species order
+	PeriodicRuns int
+	MeanPeriod   float64
+}
+
+// SpeciesResult summarizes one species over the runs of a combination
+type SpeciesResult struct {
+	Name           string
+	Plural         string
+	Extinctions    int
+	MeanExtinction float64
+	MeanCount      float64
+}
+
+// runSummary is the outcome of a single simulation
+type runSummary struct {
+	species    []config.Species
+	extinction []int // per species, the tick when its last animal died, or -1
+	mean       []float64
+	period     int // 0 when no oscillation was detected
+}
+
+// Run simulates every combination of the parameter values on top of base.
+// Results are returned in combination order, with the last parameter varying fastest
+func Run(base *config.Config, params []Param, opts Options) ([]Result, error) {
+	combinations := combine(params)
+
+	// Check every combination before starting any simulation
+	configs := make([]*config.Config, len(combinations))
+	for i, values := range combinations {
+		cfg := *base
+		for j, param := range params {
+			if err := cfg.Set(param.Name, values[j]); err != nil {
+				return nil, err
+			}
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("combination %s: %w", describe(params, values), err)
+		}
+		configs[i] = &cfg
+	}
+
+	runs := make([]runSummary, len(combinations)*opts.Runs)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(opts.Jobs, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				cfg := configs[job/opts.Runs]
+				runs[job] = simulate(cfg, opts.Seed+uint64(job%opts.Runs), opts.Ticks)
+			}
+		}()
+	}
+	for job := range runs {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+
+	results := make([]Result, len(combinations))
+	for i, values := range combinations {
+		results[i] = summarize(values, runs[i*opts.Runs:(i+1)*opts.Runs])
+	}
+	return results, nil
+}
+
+// combine returns the cartesian product of the parameter values
+func combine(params []Param) [][]string {
+	combinations := [][]string{{}}
+	for _, param := range params {
+		var next [][]string
+		for _, prefix := range combinations {
+			for _, value := range param.Values {
+				combination := append(append([]string{}, prefix...), value)
+				next = append(next, combination)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+func describe(params []Param, values []string) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		parts[i] = param.Name + "=" + values[i]
+	}
+	return strings.Join(parts, " ")
+}
+
+// simulate runs one headless simulation and summarizes its population series.
+// The oscillation period is measured on the first species that grazes, the bottom of the food chain
+func simulate(cfg *config.Config, seed uint64, ticks int) runSummary {
+	world := simulation.NewWorld(cfg, seed)
+	world.Initialize()
+
+	summary := runSummary{
+		extinction: make([]int, len(world.Populations)),
+		mean:       make([]float64, len(world.Populations)),
+	}
+	grazers := -1
+	for i, population := range world.Populations {
+		summary.species = append(summary.species, population.Species.Species)
+		summary.extinction[i] = -1
+		if grazers < 0 && population.Species.Grazes() {
+			grazers = i
+		}
+	}
+	grazers = max(grazers, 0)
+	series := make([]float64, 0, ticks)
+	totals := make([]int, len(world.Populations))
+
+	for tick := 1; tick <= ticks; tick++ {
+		world.Update()
+
+		for i, population := range world.Populations {
+			count := len(population.Animals)
+			totals[i] += count
+			if count == 0 && summary.extinction[i] < 0 {
+				summary.extinction[i] = tick
+			}
+		}
+		series = append(series, float64(len(world.Populations[grazers].Animals)))
+	}
+
+	coexisted := true
+	for i := range totals {
+		if ticks > 0 {
+			summary.mean[i] = float64(totals[i]) / float64(ticks)
+		}
+		coexisted = coexisted && summary.extinction[i] < 0
+	}
+	if coexisted {
+		// Skip the first part of the run, which is dominated by the initial placement
+		summary.period = oscillationPeriod(series[len(series)/5:])
+	}
+	return summary
+}
+
+func summarize(values []string, runs []runSummary) Result {
+	result := Result{Values: values, Runs: len(runs)}
+	for _, species := range runs[0].species {
+		result.Species = append(result.Species, SpeciesResult{Name: species.Name, Plural: species.PluralName()})
+	}
+
+	for _, run := range runs {
+		coexisted := true
+		for i := range result.Species {
+			species := &result.Species[i]
+			species.MeanCount += run.mean[i] / float64(len(runs))
+
+			if run.extinction[i] >= 0 {
+				species.Extinctions++
+				species.MeanExtinction += float64(run.extinction[i])
+				coexisted = false
+			}
+		}
+		if coexisted {
+			result.Coexisted++
+		}
+		if run.period > 0 {
+			result.PeriodicRuns++
+			result.MeanPeriod += float64(run.period)
+		}
+	}
+
+	for i := range result.Species {
+		if species := &result.Species[i]; species.Extinctions > 0 {
+			species.MeanExtinction /= float64(species.Extinctions)
+		}
+	}
+	if result.PeriodicRuns > 0 {
+		result.MeanPeriod /= float64(result.PeriodicRuns)
+	}
+	return result
+}
+
+// oscillationPeriod estimates the dominant period of a series from its autocorrelation:
+// the lag of the highest autocorrelation peak after it first drops below zero.
+// It returns 0 if the series doesn't oscillate
+func oscillationPeriod(series []float64) int {
+	n := len(series)
+	if n < 4 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range series {
+		mean += v
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, v := range series {
+		variance += (v - mean) * (v - mean)
+	}
+	if variance == 0 {
+		return 0
+	}
+
+	autocorrelation := func(lag int) float64 {
+		sum := 0.0
+		for i := 0; i+lag < n; i++ {
+			sum += (series[i] - mean) * (series[i+lag] - mean)
+		}
+		return sum / variance
+	}
+
+	crossedZero := false
+	bestLag, bestValue := 0, 0.0
+	for lag := 1; lag < n/2; lag++ {
+		value := autocorrelation(lag)
+		if !crossedZero {
+			crossedZero = value < 0
+			continue
+		}
+		if value > bestValue {
+			bestLag, bestValue = lag, value
+		}
+	}
+	return bestLag
+}
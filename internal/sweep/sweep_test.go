@@ -0,0 +1,27 @@
+package sweep
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseParam(t *testing.T) {
+	for _, test := range []struct {
+		spec   string
+		values []string
+	}{
+		{"MutationRate=0.1:0.3:0.1", []string{"0.1", "0.2", "0.3"}},
+		{"InitialFoxes=10:30:10", []string{"10", "20", "30"}},
+		{"InitialFoxes=10:25:10", []string{"10", "20"}},
+		{"ScentDecay=0.05:0.2:0.05", []string{"0.05", "0.1", "0.15", "0.2"}},
+		{"Senses=sight, scent", []string{"sight", "scent"}},
+	} {
+		param, err := ParseParam(test.spec)
+		if err != nil {
+			t.Fatalf("%s: %s", test.spec, err)
+		}
+		if !slices.Equal(param.Values, test.values) {
+			t.Fatalf("%s gave %v, expected %v", test.spec, param.Values, test.values)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sweep
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// columns lists the summary columns that follow the parameter values, with a
+// group of columns for every species
+func columns(species []SpeciesResult) []string {
+	names := []string{"runs", "coexisted"}
+	for _, s := range species {
+		names = append(names, s.Name+"_extinctions", "mean_"+s.Name+"_extinction_tick")
+	}
+	for _, s := range species {
+		names = append(names, "mean_"+s.Plural)
+	}
+	return append(names, "mean_period")
+}
+
+func (r Result) cells() []string {
+	optional := func(count int, value float64) string {
+		if count == 0 {
+			return "-"
+		}
+		return strconv.FormatFloat(value, 'f', 1, 64)
+	}
+
+	cells := append(append([]string{}, r.Values...), strconv.Itoa(r.Runs), strconv.Itoa(r.Coexisted))
+	for _, s := range r.Species {
+		cells = append(cells, strconv.Itoa(s.Extinctions), optional(s.Extinctions, s.MeanExtinction))
+	}
+	for _, s := range r.Species {
+		cells = append(cells, strconv.FormatFloat(s.MeanCount, 'f', 1, 64))
+	}
+	return append(cells, optional(r.PeriodicRuns, r.MeanPeriod))
+}
+
+// header names the parameters and the summary columns. Every combination has the
+// same species, so the first result names them
+func header(params []Param, results []Result) []string {
+	var species []SpeciesResult
+	if len(results) > 0 {
+		species = results[0].Species
+	}
+
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	return append(names, columns(species)...)
+}
+
+// WriteTable writes the results as an aligned plain-text table
+func WriteTable(out io.Writer, params []Param, results []Result) error {
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	writeRow := func(cells []string) {
+		for _, cell := range cells {
+			fmt.Fprintf(table, "%s\t", cell)
+		}
+		fmt.Fprintln(table)
+	}
+
+	writeRow(header(params, results))
+	for _, result := range results {
+		writeRow(result.cells())
+	}
+	return table.Flush()
+}
+
+// WriteCSV writes the results as CSV with a header row
+func WriteCSV(out io.Writer, params []Param, results []Result) error {
+	writer := csv.NewWriter(out)
+	writer.Write(header(params, results))
+	for _, result := range results {
+		writer.Write(result.cells())
+	}
+	writer.Flush()
+	return writer.Error()
+}
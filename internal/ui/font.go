@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Glyphs of a tiny 3x5 pixel font, written row by row from the top.
+// It covers what the overlays need without pulling in SDL_ttf
+var glyphs = map[rune]string{
+	'0': "111101101101111", '1': "010110010010111", '2': "111001111100111", '3': "111001111001111",
+	'4': "101101111001001", '5': "111100111001111", '6': "111100111101111", '7': "111001001001001",
+	'8': "111101111101111", '9': "111101111001111",
+	'A': "010101111101101", 'B': "110101110101110", 'C': "011100100100011", 'D': "110101101101110",
+	'E': "111100110100111", 'F': "111100110100100", 'G': "011100101101011", 'H': "101101111101101",
+	'I': "111010010010111", 'J': "001001001101010", 'K': "101101110101101", 'L': "100100100100111",
+	'M': "101111111101101", 'N': "110101101101101", 'O': "010101101101010", 'P': "110101110100100",
+	'Q': "010101101110011", 'R': "110101110101101", 'S': "011100010001110", 'T': "111010010010010",
+	'U': "101101101101111", 'V': "101101101101010", 'W': "101101111111101", 'X': "101101010101101",
+	'Y': "101101010010010", 'Z': "111001010100111",
+	' ': "000000000000000", ':': "000010000010000", '/': "001001010100100", '#': "101111101111101",
+	'-': "000000111000000", '.': "000000000000010", ',': "000000000010100", '(': "010100100100010",
+	')': "010001001001010", '%': "101001010100101", '+': "000010111010000", '[': "110100100100110",
+	']': "011001001001011", '=': "000111000111000",
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// textWidth returns the width in pixels of text drawn at the given scale
+func textWidth(text string, scale int32) int32 {
+	return int32(len(text)) * (glyphWidth + 1) * scale
+}
+
+// textHeight returns the height in pixels of one line of text drawn at the given scale
+func textHeight(scale int32) int32 {
+	return glyphHeight * scale
+}
+
+// drawText draws text with its top-left corner at x, y in the current draw color.
+// Letters are drawn uppercase, characters missing from the font are skipped
+func (r *Renderer) drawText(x, y int32, text string, scale int32) {
+	for _, char := range strings.ToUpper(text) {
+		if glyph, ok := glyphs[char]; ok {
+			for i, bit := range glyph {
+				if bit == '1' {
+					col, row := int32(i%glyphWidth), int32(i/glyphWidth)
+					r.renderer.FillRect(&sdl.Rect{X: x + col*scale, Y: y + row*scale, W: scale, H: scale})
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
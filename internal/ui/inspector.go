@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"foxes-rabbits-simulation/internal/simulation"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	inspectorScale   = 2
+	inspectorPadding = 6
+	inspectorMargin  = 10
+)
+
+// selection is the cell or animal picked in inspect mode. A selected animal
+// is followed as it moves, and stays shown as dead once it dies
+type selection struct {
+	cell   simulation.Position
+	animal *simulation.Animal
+}
+
+// Select picks the animal on a cell for the inspector, or the cell itself if it's empty
+func (r *Renderer) Select(world *simulation.World, x, y int) {
+	if x < 0 || x >= world.Width || y < 0 || y >= world.Height {
+		r.selected = nil
+		return
+	}
+	r.selected = &selection{
+		cell:   simulation.Position{X: x, Y: y},
+		animal: world.AnimalAt(x, y),
+	}
+}
+
+// inspectorLines describes the selection, one line per stat
+func (r *Renderer) inspectorLines(world *simulation.World) []string {
+	var lines []string
+
+	if base := r.selected.animal; base != nil {
+		if !base.IsDead() {
+			r.selected.cell = base.Position
+		}
+
+		lines = append(lines,
+			describeAnimal(base),
+			fmt.Sprintf("Pos %d,%d", base.Position.X, base.Position.Y),
+			fmt.Sprintf("Energy %d", base.Energy),
+			fmt.Sprintf("Since eaten %d", base.TurnsSinceEaten),
+			fmt.Sprintf("Since repro %d", base.TurnsSinceReproduction),
+			describeAge(base),
+			fmt.Sprintf("Speed %.2f", base.Traits.Speed),
+			fmt.Sprintf("Vision %d", base.Traits.Vision),
+			fmt.Sprintf("Metabolism %.2f", base.Traits.Metabolism),
+			fmt.Sprintf("Repro at %d", base.Traits.ReproductionThreshold),
+		)
+		if base.Pregnancy != nil {
+			lines = append(lines, fmt.Sprintf("Pregnant, %d young in %d", base.Pregnancy.LitterSize, base.Pregnancy.TicksLeft))
+		}
+		if base.Burrow != nil {
+			lines = append(lines, "Hidden in burrow")
+		}
+		if base.IsDead() {
+			lines = append(lines, "Dead")
+		}
+	} else {
+		lines = append(lines, fmt.Sprintf("Cell %d,%d", r.selected.cell.X, r.selected.cell.Y))
+	}
+
+	if burrow := world.BurrowAt(r.selected.cell.X, r.selected.cell.Y); burrow != nil {
+		lines = append(lines, fmt.Sprintf("Burrow %d/%d", burrow.Occupants, r.config.BurrowCapacity))
+	}
+
+	grass := world.GrassGrid[r.selected.cell.X][r.selected.cell.Y]
+	return append(lines,
+		fmt.Sprintf("Terrain %s", world.TerrainGrid[r.selected.cell.X][r.selected.cell.Y]),
+		fmt.Sprintf("Grass %d/%d", grass.Amount, grass.MaxAmount),
+		fmt.Sprintf("Regrowth %d/%d", grass.RegrowthTimer, r.config.GrassRegrowthTimer),
+	)
+}
+
+// describeAnimal names the species, ID and, with sexual reproduction, the sex of an animal
+func describeAnimal(animal *simulation.Animal) string {
+	description := fmt.Sprintf("%s #%d", animal.Species.Name, animal.ID)
+	if animal.Sex != simulation.Asexual {
+		description += " " + animal.Sex.String()
+	}
+	return description
+}
+
+// describeAge shows an animal's age, and its lifespan if it has one
+func describeAge(a *simulation.Animal) string {
+	if a.Lifespan > 0 {
+		return fmt.Sprintf("Age %d/%d", a.Age, a.Lifespan)
+	}
+	return fmt.Sprintf("Age %d", a.Age)
+}
+
+// drawInspector outlines the selected cell and draws a panel with its stats next to it
+func (r *Renderer) drawInspector(world *simulation.World) {
+	if r.selected == nil {
+		return
+	}
+	windowWidth, windowHeight := r.window.GetSize()
+
+	lines := r.inspectorLines(world)
+
+	// Outline the selected cell
+	size := int32(r.config.AnimalSize)
+	cellX, cellY := int32(r.selected.cell.X)*size, int32(r.selected.cell.Y)*size
+	r.renderer.SetDrawColor(255, 255, 0, 255)
+	r.renderer.DrawRect(&sdl.Rect{X: cellX - 2, Y: cellY - 2, W: size + 4, H: size + 4})
+
+	// Place the panel beside the cell, flipping it to the other side near the window edges
+	width, height := panelSize(lines)
+	x, y := cellX+size+inspectorMargin, cellY
+	if x+width > windowWidth {
+		x = cellX - inspectorMargin - width
+	}
+	if y+height > windowHeight {
+		y = windowHeight - height
+	}
+	r.drawPanel(max(x, 0), max(y, 0), lines)
+}
+
+// panelSize returns the size of a panel holding the given lines
+func panelSize(lines []string) (int32, int32) {
+	width := int32(0)
+	for _, line := range lines {
+		width = max(width, textWidth(line, inspectorScale))
+	}
+	lineHeight := textHeight(inspectorScale) + inspectorScale*2
+	return width + 2*inspectorPadding, int32(len(lines))*lineHeight - inspectorScale*2 + 2*inspectorPadding
+}
+
+// drawPanel draws lines of white text on a translucent dark background
+func (r *Renderer) drawPanel(x, y int32, lines []string) {
+	width, height := panelSize(lines)
+
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	r.renderer.SetDrawColor(0, 0, 0, 190)
+	r.renderer.FillRect(&sdl.Rect{X: x, Y: y, W: width, H: height})
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+
+	r.renderer.SetDrawColor(255, 255, 255, 255)
+	lineHeight := textHeight(inspectorScale) + inspectorScale*2
+	for i, line := range lines {
+		r.drawText(x+inspectorPadding, y+inspectorPadding+int32(i)*lineHeight, line, inspectorScale)
+	}
+}
@@ -3,14 +3,24 @@ package ui
 import (
 	"foxes-rabbits-simulation/internal/config"
 	"foxes-rabbits-simulation/internal/simulation"
+	"math"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 type MouseAction struct {
-	Action string // "AddFox", "AddRabbit", or "" // ToDo: "RemoveAnimal"?
-	X      int
-	Y      int
+	Action  string // One of the tools, or "" when the mouse isn't used
+	Species string // The species placed by the Add action
+	X       int
+	Y       int
+	Radius  int // Brush radius in cells, the action applies to every cell within it
+}
+
+// Input is everything the user asked for since the last call to HandleEvents
+type Input struct {
+	Mouse      MouseAction
+	KeyActions []string // "TogglePause", "Step", "SpeedUp" or "SlowDown", in the order pressed
+	Quit       bool     // Set when any window was closed
 }
 
 type Renderer struct {
@@ -19,6 +29,19 @@ type Renderer struct {
 	config         *config.Config
 	leftMouseDown  bool
 	rightMouseDown bool
+
+	// The left mouse button applies tool with a brush of brushRadius cells,
+	// the right one adds an animal of the first species hunting others
+	tools       []tool
+	tool        tool
+	rightTool   tool
+	brushRadius int
+
+	// With the Inspect tool, clicks select a cell or animal
+	selected *selection
+
+	// scentOverlay is 1 plus the index of the population whose scent is shown, 0 shows none
+	scentOverlay int
 }
 
 func NewRenderer(title string, width, height int, cfg *config.Config) (*Renderer, error) {
@@ -30,23 +53,56 @@ func NewRenderer(title string, width, height int, cfg *config.Config) (*Renderer
 
 	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
 	if err != nil {
+		window.Destroy()
 		return nil, err
 	}
 
-	return &Renderer{
+	tools := newTools(cfg)
+	r := &Renderer{
 		window:   window,
 		renderer: renderer,
 		config:   cfg,
-	}, nil
+		tools:    tools,
+		tool:     tools[0],
+	}
+	for _, species := range cfg.AllSpecies() {
+		if len(species.Prey) > 0 && !species.Eats(config.Grass) {
+			r.rightTool = tool{name: "Add", label: capitalize(species.Name), species: species.Name}
+			break
+		}
+	}
+	return r, nil
 }
 
-func (r *Renderer) HandleEvents() MouseAction {
-	var action MouseAction
+// HandleEvents drains the SDL event queue, which holds the events of every window,
+// so closing the chart window is reported here as well
+func (r *Renderer) HandleEvents() Input {
+	var input Input
 
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 		switch e := event.(type) {
+		case *sdl.QuitEvent:
+			input.Quit = true
+		case *sdl.WindowEvent:
+			if e.Event == sdl.WINDOWEVENT_CLOSE {
+				input.Quit = true
+			}
+		case *sdl.KeyboardEvent:
+			if e.Type == sdl.KEYDOWN {
+				if keyAction := keyActions[e.Keysym.Sym]; keyAction != "" {
+					input.KeyActions = append(input.KeyActions, keyAction)
+				} else {
+					r.handleToolKey(e.Keysym.Sym)
+				}
+			}
 		case *sdl.MouseButtonEvent:
-			if e.Type == sdl.MOUSEBUTTONDOWN {
+			if r.tool.name == "Inspect" {
+				windowID, _ := r.window.GetID()
+				if e.Type == sdl.MOUSEBUTTONDOWN && e.Button == sdl.BUTTON_LEFT && e.WindowID == windowID {
+					size := int32(r.config.AnimalSize)
+					input.Mouse = MouseAction{Action: "Inspect", X: int(e.X / size), Y: int(e.Y / size)}
+				}
+			} else if e.Type == sdl.MOUSEBUTTONDOWN {
 				r.leftMouseDown = e.Button == sdl.BUTTON_LEFT
 				r.rightMouseDown = e.Button == sdl.BUTTON_RIGHT
 			} else if e.Type == sdl.MOUSEBUTTONUP {
@@ -59,19 +115,31 @@ func (r *Renderer) HandleEvents() MouseAction {
 		}
 	}
 
-	if r.leftMouseDown || r.rightMouseDown {
+	if r.tool.name != "Inspect" && (r.leftMouseDown || r.rightMouseDown) {
 		mouseX, mouseY, _ := sdl.GetMouseState()
 		gridX := int(mouseX) / r.config.AnimalSize
 		gridY := int(mouseY) / r.config.AnimalSize
 
-		if r.leftMouseDown {
-			action = MouseAction{Action: "AddRabbit", X: gridX, Y: gridY}
-		} else if r.rightMouseDown {
-			action = MouseAction{Action: "AddFox", X: gridX, Y: gridY}
+		used := r.tool
+		if !r.leftMouseDown {
+			used = r.rightTool
 		}
+		input.Mouse = MouseAction{Action: used.name, Species: used.species, X: gridX, Y: gridY, Radius: r.brushRadius}
 	}
 
-	return action
+	return input
+}
+
+// keyActions maps keys to the actions they trigger
+var keyActions = map[sdl.Keycode]string{
+	sdl.K_SPACE:    "TogglePause",
+	sdl.K_n:        "Step",
+	sdl.K_PERIOD:   "Step",
+	sdl.K_PLUS:     "SpeedUp",
+	sdl.K_EQUALS:   "SpeedUp",
+	sdl.K_KP_PLUS:  "SpeedUp",
+	sdl.K_MINUS:    "SlowDown",
+	sdl.K_KP_MINUS: "SlowDown",
 }
 
 func (r *Renderer) Render(world *simulation.World) {
@@ -79,38 +147,102 @@ func (r *Renderer) Render(world *simulation.World) {
 	r.renderer.SetDrawColor(255, 255, 255, 255)
 	r.renderer.Clear()
 
-	// Draw grass
+	// Draw terrain and grass
 	for x := 0; x < world.Width; x++ {
 		for y := 0; y < world.Height; y++ {
-			r.drawGrass(x, y, world.GrassGrid[x][y].Amount)
+			switch world.TerrainGrid[x][y] {
+			case simulation.Meadow:
+				r.drawGrass(x, y, world.GrassGrid[x][y].Amount, r.config.GrassBaseColor)
+			case simulation.Forest:
+				r.drawGrass(x, y, world.GrassGrid[x][y].Amount, r.config.ForestColor)
+			case simulation.Water:
+				r.drawCell(x, y, r.config.WaterColor)
+			case simulation.Rock:
+				r.drawCell(x, y, r.config.RockColor)
+			}
 		}
 	}
 
-	// Draw rabbits and foxes
-	for _, rabbit := range world.Rabbits {
-		r.drawAnimal(rabbit.Position.X, rabbit.Position.Y, rabbit.Config.RabbitColor)
+	// Draw burrows over the terrain
+	for _, burrow := range world.Burrows {
+		r.drawCell(burrow.Position.X, burrow.Position.Y, r.config.BurrowColor)
 	}
 
-	for _, fox := range world.Foxes {
-		r.drawAnimal(fox.Position.X, fox.Position.Y, fox.Config.FoxColor)
+	if r.scentOverlay > 0 && r.scentOverlay <= len(world.Populations) {
+		r.drawScent(world, world.Populations[r.scentOverlay-1])
 	}
 
+	// Draw the animals of every species, except those hidden in burrows
+	for _, population := range world.Populations {
+		for _, animal := range population.Animals {
+			if animal.Burrow != nil {
+				continue
+			}
+			r.drawCell(animal.Position.X, animal.Position.Y, population.Species.Color)
+			if animal.Pregnancy != nil {
+				r.drawPregnancy(animal.Position.X, animal.Position.Y)
+			}
+		}
+	}
+
+	if r.tool.name == "Inspect" {
+		r.drawInspector(world)
+	} else {
+		r.drawBrush(world)
+	}
+	r.drawToolbar()
+
 	r.renderer.Present()
 }
 
-func (r *Renderer) drawAnimal(x, y int, color sdl.Color) {
+// drawCell fills a cell with a solid color
+func (r *Renderer) drawCell(x, y int, color config.Color) {
 	r.renderer.SetDrawColor(color.R, color.G, color.B, color.A)
 	size := r.config.AnimalSize
 	rect := sdl.Rect{X: int32(x * size), Y: int32(y * size), W: int32(size), H: int32(size)}
 	r.renderer.FillRect(&rect)
 }
 
-func (r *Renderer) drawGrass(x, y int, amount int) {
+// drawScent tints every cell with the color of the population, more opaque the stronger
+// its scent there compared to the strongest scent in the world
+func (r *Renderer) drawScent(world *simulation.World, population *simulation.Population) {
+	strongest := 0.0
+	for _, column := range population.Scent {
+		for _, scent := range column {
+			strongest = max(strongest, scent)
+		}
+	}
+	if strongest <= 0 {
+		return
+	}
+
+	color := population.Species.Color
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	for x, column := range population.Scent {
+		for y, scent := range column {
+			// The square root keeps faint trails visible next to fresh ones
+			if alpha := uint8(200 * math.Sqrt(scent/strongest)); alpha > 0 {
+				r.drawCell(x, y, config.Color{R: color.R, G: color.G, B: color.B, A: alpha})
+			}
+		}
+	}
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+}
+
+// drawPregnancy marks a pregnant animal with a white dot in the middle of its cell
+func (r *Renderer) drawPregnancy(x, y int) {
+	size := int32(r.config.AnimalSize)
+	dot := max(size/3, 1)
+	r.renderer.SetDrawColor(255, 255, 255, 255)
+	r.renderer.FillRect(&sdl.Rect{X: int32(x)*size + (size-dot)/2, Y: int32(y)*size + (size-dot)/2, W: dot, H: dot})
+}
+
+// drawGrass shades a cell from baseColor, brightening its green with the amount of grass
+func (r *Renderer) drawGrass(x, y int, amount int, baseColor config.Color) {
 	// Clamp amount between 0 and max
 	amount = max(0, min(amount, r.config.GrassMaxAmount))
 
 	// Calculate green intensity
-	baseColor := r.config.GrassBaseColor
 	ratio := float64(amount) / float64(r.config.GrassMaxAmount)
 	greenValue := uint8(float64(baseColor.G) + ratio*(255.0-float64(baseColor.G)))
 
@@ -124,3 +256,9 @@ func (r *Renderer) drawGrass(x, y int, amount int) {
 func (r *Renderer) SetTitle(title string) {
 	r.window.SetTitle(title)
 }
+
+// Destroy releases the SDL renderer and window
+func (r *Renderer) Destroy() {
+	r.renderer.Destroy()
+	r.window.Destroy()
+}
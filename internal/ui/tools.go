@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/simulation"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// maxBrushRadius limits how large the brush can be made with the bracket keys
+const maxBrushRadius = 20
+
+// tool is a mouse tool of the palette, selected by its number key
+type tool struct {
+	name    string // "Add", "RemoveAnimal", "PaintGrass", "EraseGrass" or "Inspect"
+	label   string
+	species string // The species an Add tool places
+}
+
+// newTools builds the palette: an Add tool for every species, those eating grass
+// first, followed by the tools editing the world
+func newTools(cfg *config.Config) []tool {
+	var grazers, hunters []tool
+	for _, species := range cfg.AllSpecies() {
+		add := tool{name: "Add", label: capitalize(species.Name), species: species.Name}
+		if species.Eats(config.Grass) {
+			grazers = append(grazers, add)
+		} else {
+			hunters = append(hunters, add)
+		}
+	}
+
+	return append(append(grazers, hunters...),
+		tool{name: "RemoveAnimal", label: "Remove"},
+		tool{name: "PaintGrass", label: "Grass+"},
+		tool{name: "EraseGrass", label: "Grass-"},
+		tool{name: "Inspect", label: "Inspect"},
+	)
+}
+
+// capitalize upper-cases the first letter of a species name for labels and titles
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// handleToolKey switches tools and resizes the brush
+func (r *Renderer) handleToolKey(key sdl.Keycode) {
+	switch key {
+	case sdl.K_LEFTBRACKET:
+		r.brushRadius = max(r.brushRadius-1, 0)
+	case sdl.K_RIGHTBRACKET:
+		r.brushRadius = min(r.brushRadius+1, maxBrushRadius)
+	case sdl.K_i:
+		// I toggles between inspecting and the first tool
+		if r.tool.name == "Inspect" {
+			r.tool = r.tools[0]
+		} else {
+			r.tool = tool{name: "Inspect", label: "Inspect"}
+		}
+		r.selected = nil
+	case sdl.K_ESCAPE:
+		r.selected = nil
+	case sdl.K_s:
+		// S shows the scent of each species in turn, then none
+		r.scentOverlay = (r.scentOverlay + 1) % (len(r.config.AllSpecies()) + 1)
+	default:
+		// Number keys pick the first nine tools
+		if i := int(key - sdl.K_1); i >= 0 && i < min(len(r.tools), 9) {
+			r.tool = r.tools[i]
+			r.selected = nil
+		}
+	}
+}
+
+// drawBrush highlights the cells the current tool would affect under the mouse,
+// wrapping around the edges like the tool does on a torus
+func (r *Renderer) drawBrush(world *simulation.World) {
+	mouseX, mouseY, _ := sdl.GetMouseState()
+	size := int32(r.config.AnimalSize)
+	centerX, centerY := int(mouseX/size), int(mouseY/size)
+	radius := r.brushRadius
+
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	r.renderer.SetDrawColor(255, 255, 255, 70)
+	for dx := -radius; dx <= radius; dx++ {
+		for dy := -radius; dy <= radius; dy++ {
+			x, y, ok := world.Wrap(centerX+dx, centerY+dy)
+			if ok && dx*dx+dy*dy <= radius*radius {
+				r.renderer.FillRect(&sdl.Rect{X: int32(x) * size, Y: int32(y) * size, W: size, H: size})
+			}
+		}
+	}
+	r.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+}
+
+// drawToolbar shows the tool palette along the bottom of the window, marking the current tool
+func (r *Renderer) drawToolbar() {
+	labels := make([]string, 0, len(r.tools)+1)
+	for i, tool := range r.tools {
+		label := tool.label
+		if i < 9 {
+			label = fmt.Sprintf("%d %s", i+1, tool.label)
+		}
+		if tool == r.tool {
+			label = "[" + label + "]"
+		}
+		labels = append(labels, label)
+	}
+	labels = append(labels, fmt.Sprintf("Brush %d", r.brushRadius))
+	if species := r.config.AllSpecies(); r.scentOverlay > 0 && r.scentOverlay <= len(species) {
+		labels = append(labels, "Scent "+species[r.scentOverlay-1].PluralName())
+	}
+
+	_, windowHeight := r.window.GetSize()
+	line := strings.Join(labels, "  ")
+	r.drawPanel(inspectorMargin, windowHeight-inspectorMargin-textHeight(inspectorScale)-2*inspectorPadding, []string{line})
+}
@@ -1,72 +1,214 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"foxes-rabbits-simulation/internal/chart"
 	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/headless"
+	"foxes-rabbits-simulation/internal/layout"
 	"foxes-rabbits-simulation/internal/simulation"
-	"foxes-rabbits-simulation/internal/ui"
+	"foxes-rabbits-simulation/internal/stats"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
-
-	"github.com/veandco/go-sdl2/sdl"
 )
 
 func main() {
-	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize SDL: %s\n", err)
+	if len(os.Args) > 1 && os.Args[1] == "sweep" {
+		if err := runSweep(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Sweep failed: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	headlessMode := flag.Bool("headless", false, "run without a window and print population stats")
+	ticks := flag.Int("ticks", 1000, "number of ticks to simulate in headless mode")
+	reportEvery := flag.Int("report", 100, "print population stats every N ticks in headless mode")
+	seed := flag.Uint64("seed", 0, "random seed, runs with the same seed and config are identical (0 picks one from the clock)")
+	configPath := flag.String("config", "", "load simulation parameters from a JSON file")
+	var overrides config.Overrides
+	flag.Var(&overrides, "set", "override a config field, e.g. -set FoxEnergyGainFromRabbit=120 (repeatable)")
+	loadPath := flag.String("load", "", "resume from a world snapshot instead of starting a new world")
+	mapPath := flag.String("map", "", "start from a layout map, a PNG image or an ASCII text file, instead of random placement")
+	savePath := flag.String("save", "", "write a world snapshot when the simulation ends")
+	statsPath := flag.String("stats", "", "write per-tick population statistics to a file")
+	statsFormat := flag.String("stats-format", "", "statistics file format, csv or ndjson (default: from the file extension)")
+	eventsPath := flag.String("events", "", "write simulation events to an NDJSON file")
+	eventKinds := flag.String("event-kinds", "", "comma-separated event kinds to log, e.g. born,starved,eaten (default: all)")
+	printConfig := flag.Bool("print-config", false, "print the effective config as JSON and exit")
+	flag.Parse()
+
+	if *loadPath != "" && *mapPath != "" {
+		fmt.Fprintln(os.Stderr, "-load and -map can't be used together")
+		os.Exit(1)
+	}
+	if *loadPath != "" && *configPath != "" {
+		fmt.Fprintln(os.Stderr, "-load and -config can't be used together, the snapshot carries its config")
 		os.Exit(1)
 	}
-	defer sdl.Quit()
 
 	cfg := config.NewConfig()
-	world := simulation.NewWorld(cfg)
-	world.Initialize(cfg.InitialFoxes, cfg.InitialRabbits)
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load config: %s\n", err)
+			os.Exit(1)
+		}
+	}
 
-	renderer, err := ui.NewRenderer("Foxes and Rabbits Simulation",
-		cfg.WorldWidth*cfg.AnimalSize, cfg.WorldHeight*cfg.AnimalSize, cfg)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize renderer: %s\n", err)
-		os.Exit(1)
+	// With -load, overrides apply to the snapshot's config instead
+	if *loadPath == "" {
+		if err := overrides.Apply(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -set override: %s\n", err)
+			os.Exit(1)
+		}
 	}
 
-	chartWindow, err := chart.NewChartWindow("Population Chart", cfg.WorldWidth*cfg.AnimalSize, cfg.WorldHeight*cfg.AnimalSize)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize chart window: %s\n", err)
+	var world *simulation.World
+	if *loadPath != "" {
+		var err error
+		if world, err = loadWorld(*loadPath, overrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load snapshot: %s\n", err)
+			os.Exit(1)
+		}
+		// The snapshot carries the config it was running with, -set overrides apply on top
+		cfg = world.Config
+	} else {
+		if *seed == 0 {
+			*seed = uint64(time.Now().UnixNano())
+		}
+		fmt.Fprintf(os.Stderr, "Seed: %d\n", *seed)
+
+		if *mapPath != "" {
+			worldLayout, err := layout.Load(*mapPath, cfg)
+			if err == nil {
+				world, err = worldLayout.NewWorld(cfg, *seed)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load map: %s\n", err)
+				os.Exit(1)
+			}
+			// The map sets the world size and initial populations
+			cfg = world.Config
+		} else {
+			world = simulation.NewWorld(cfg, *seed)
+			world.Initialize()
+		}
+	}
+
+	// Printed once the world is built, as a snapshot or map may change the config
+	if *printConfig {
+		world.Config.Write(os.Stdout)
+		return
+	}
+
+	// Stop cleanly on Ctrl+C so recorders are flushed and the snapshot is saved
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var onTick func(*simulation.World)
+	var recorder *stats.Recorder
+	if *statsPath != "" {
+		var err error
+		if recorder, err = stats.Create(*statsPath, stats.Format(*statsFormat)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create stats file: %s\n", err)
+			os.Exit(1)
+		}
+		recorder.Record(world)
+		onTick = recorder.Record
+	}
+
+	var eventLog *stats.EventLog
+	if *eventsPath != "" {
+		var err error
+		if eventLog, err = createEventLog(*eventsPath, *eventKinds); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create event log: %s\n", err)
+			closeOutputs(recorder, eventLog)
+			os.Exit(1)
+		}
+		world.Subscribe(eventLog.Log)
+	}
+
+	var runErr error
+	if *headlessMode {
+		headless.Run(ctx, world, *ticks, *reportEvery, os.Stdout, onTick)
+	} else {
+		runErr = runGUI(ctx, world, cfg, onTick)
+	}
+
+	failed := !closeOutputs(recorder, eventLog)
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", runErr)
 		os.Exit(1)
 	}
 
-	chartWindow.AddDataPoint(len(world.Foxes), len(world.Rabbits))
+	if *savePath != "" {
+		if err := saveWorld(world, *savePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save snapshot: %s\n", err)
+			failed = true
+		}
+	}
 
-	frameDelay := cfg.FrameTime * time.Millisecond
+	if failed {
+		os.Exit(1)
+	}
+}
 
-	for {
-		// Handle events in both windows
-		mouseAction := renderer.HandleEvents()
+// closeOutputs flushes and closes whichever recorders are open, reporting whether all succeeded
+func closeOutputs(recorder *stats.Recorder, eventLog *stats.EventLog) bool {
+	ok := true
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write stats file: %s\n", err)
+			ok = false
+		}
+	}
+	if eventLog != nil {
+		if err := eventLog.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write event log: %s\n", err)
+			ok = false
+		}
+	}
+	return ok
+}
 
-		// Process mouse actions
-		if mouseAction.Action != "" && !world.IsPositionOccupied(mouseAction.X, mouseAction.Y) {
-			switch mouseAction.Action {
-			case "AddRabbit":
-				world.Rabbits = append(world.Rabbits, simulation.NewRabbit(mouseAction.X, mouseAction.Y, cfg))
-			case "AddFox":
-				world.Foxes = append(world.Foxes, simulation.NewFox(mouseAction.X, mouseAction.Y, cfg))
+func createEventLog(path, kindList string) (*stats.EventLog, error) {
+	var kinds []simulation.EventKind
+	if kindList != "" {
+		for _, name := range strings.Split(kindList, ",") {
+			kind, err := simulation.ParseEventKind(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
 			}
+			kinds = append(kinds, kind)
 		}
+	}
+	return stats.CreateEventLog(path, kinds...)
+}
 
-		// Update simulation and UI
-		world.Update()
+func loadWorld(path string, overrides config.Overrides) (*simulation.World, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-		// Update titles
-		foxCount, rabbitCount := len(world.Foxes), len(world.Rabbits)
-		renderer.SetTitle(fmt.Sprintf("Foxes and Rabbits Simulation - Foxes: %d | Rabbits: %d", foxCount, rabbitCount))
-		chartWindow.SetTitle(fmt.Sprintf("Population Chart - Foxes: %d | Rabbits: %d", foxCount, rabbitCount))
+	return simulation.LoadWorld(file, overrides)
+}
 
-		// Render windows
-		renderer.Render(world)
-		chartWindow.AddDataPoint(foxCount, rabbitCount)
-		chartWindow.Render()
+func saveWorld(world *simulation.World, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
 
-		time.Sleep(frameDelay)
+	if err := world.Save(file); err != nil {
+		file.Close()
+		return err
 	}
+	return file.Close()
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"foxes-rabbits-simulation/internal/config"
+	"foxes-rabbits-simulation/internal/sweep"
+	"os"
+	"runtime"
+)
+
+// runSweep implements the sweep subcommand, which runs headless simulations
+// for every combination of parameter values and prints a summary per combination
+func runSweep(args []string) error {
+	flags := flag.NewFlagSet("sweep", flag.ExitOnError)
+	var paramSpecs, overrides config.Overrides
+	flags.Var(&paramSpecs, "param", "parameter range as Name=start:stop:step or Name=v1,v2,... (repeatable)")
+	flags.Var(&overrides, "set", "override a config field for every run, e.g. -set WorldWidth=200 (repeatable)")
+	configPath := flags.String("config", "", "load base simulation parameters from a JSON file")
+	ticks := flags.Int("ticks", 2000, "number of ticks per simulation")
+	runs := flags.Int("runs", 1, "simulations per combination, each with its own seed")
+	seed := flags.Uint64("seed", 1, "seed of the first run of each combination, later runs use the following seeds")
+	jobs := flags.Int("jobs", runtime.NumCPU(), "simulations to run concurrently")
+	format := flags.String("format", "table", "output format, table or csv")
+	flags.Parse(args)
+
+	if len(paramSpecs) == 0 {
+		return fmt.Errorf("sweep needs at least one -param")
+	}
+	if *runs < 1 || *ticks < 1 {
+		return fmt.Errorf("-runs and -ticks must be positive")
+	}
+	if *format != "table" && *format != "csv" {
+		return fmt.Errorf("unknown format %q, expected table or csv", *format)
+	}
+
+	cfg := config.NewConfig()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			return err
+		}
+	}
+	if err := overrides.Apply(cfg); err != nil {
+		return err
+	}
+
+	params := make([]sweep.Param, 0, len(paramSpecs))
+	for _, spec := range paramSpecs {
+		param, err := sweep.ParseParam(spec)
+		if err != nil {
+			return err
+		}
+		params = append(params, param)
+	}
+
+	results, err := sweep.Run(cfg, params, sweep.Options{Ticks: *ticks, Runs: *runs, Seed: *seed, Jobs: *jobs})
+	if err != nil {
+		return err
+	}
+
+	if *format == "csv" {
+		return sweep.WriteCSV(os.Stdout, params, results)
+	}
+	return sweep.WriteTable(os.Stdout, params, results)
+}